@@ -1,11 +1,31 @@
 package splitwise
 
+import (
+	"context"
+	"net/http"
+)
+
 // AuthProvider knows how to provide authentication for calling the service APIs
 type AuthProvider interface {
 	// Auth Provides the auth token header used in calling the APIs
 	Auth() (string, error)
 }
 
+// RequestSigner is implemented by the AuthProviders which need the whole request to authenticate it, e.g. to sign it.
+// The client calls Sign instead of Auth for these providers, right before sending each request.
+type RequestSigner interface {
+	// Sign adds the authentication of the provider to the request, usually as the Authorization header
+	Sign(req *http.Request) error
+}
+
+// ContextAuthProvider is implemented by the AuthProviders which may call a service to provide the token, e.g. to refresh
+// it. The client calls AuthContext instead of Auth for these providers with the context of the request, so providing
+// the token is cancelled with the request.
+type ContextAuthProvider interface {
+	// AuthContext provides the auth token header used in calling the APIs, giving up when ctx is done
+	AuthContext(ctx context.Context) (string, error)
+}
+
 // NewAPIKeyAuth returns a new AuthProvider that is working with API key
 func NewAPIKeyAuth(apiKey string) AuthProvider {
 	return &apiKeyAuthProvider{apiKey: apiKey}
@@ -19,3 +39,51 @@ type apiKeyAuthProvider struct {
 func (a apiKeyAuthProvider) Auth() (string, error) {
 	return a.apiKey, nil
 }
+
+func (a apiKeyAuthProvider) String() string {
+	return "API key"
+}
+
+// authorize adds the authentication of the AuthProvider to the request. The providers implementing RequestSigner sign
+// the request themselves, the others provide a bearer token.
+func authorize(provider AuthProvider, req *http.Request) error {
+	if signer, ok := provider.(RequestSigner); ok {
+		return signer.Sign(req)
+	}
+
+	var token string
+	var err error
+	if contextProvider, ok := provider.(ContextAuthProvider); ok {
+		token, err = contextProvider.AuthContext(req.Context())
+	} else {
+		token, err = provider.Auth()
+	}
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
+
+// authLock is a mutex which can be given up on when a context is done, so that the requests waiting for a token being
+// refreshed by another request don't outlive their own contexts
+type authLock chan struct{}
+
+func newAuthLock() authLock {
+	return make(authLock, 1)
+}
+
+func (l authLock) lock(ctx context.Context) error {
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l authLock) unlock() {
+	<-l
+}
@@ -2,7 +2,6 @@ package splitwise
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 )
 
@@ -45,33 +44,8 @@ type categoriesResponse struct {
 // a subcategory, not a parent category. If you intend for an expense to be represented by the parent category and
 // nothing more specific, please use the "Other" subcategory.
 func (c client) Categories(ctx context.Context) ([]Category, error) {
-	url := c.baseURL + "/api/v3.0/get_categories"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	token, err := c.AuthProvider.Auth()
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("Authorization", "Bearer "+token)
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
-
-	err = c.checkError(res)
-	if err != nil {
-		return nil, err
-	}
-
 	var response categoriesResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
+	err := c.call(ctx, "Categories", http.MethodGet, "get_categories", nil, nil, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,286 @@
+package splitwise
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAPIKeyEnv is the environment variable that NewEnvAuth reads the API key from by default
+	DefaultAPIKeyEnv = "SPLITWISE_API_KEY"
+)
+
+// DefaultConfigFilePath returns the path of the config file that NewConfigFileAuth reads by default, which is
+// splitwise/config.json in the user's config directory
+func DefaultConfigFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "splitwise", "config.json"), nil
+}
+
+// NewEnvAuth returns a new AuthProvider that reads the API key from an environment variable. DefaultAPIKeyEnv is used if
+// name is empty.
+func NewEnvAuth(name string) AuthProvider {
+	if name == "" {
+		name = DefaultAPIKeyEnv
+	}
+
+	return &envAuthProvider{name: name}
+}
+
+type envAuthProvider struct {
+	name string
+}
+
+// Auth Provides the auth token header used in calling the APIs
+func (a envAuthProvider) Auth() (string, error) {
+	apiKey := os.Getenv(a.name)
+	if apiKey == "" {
+		return "", fmt.Errorf("%w: %s is not set", ErrNoCredentials, a.name)
+	}
+
+	return apiKey, nil
+}
+
+func (a envAuthProvider) String() string {
+	return "environment variable " + a.name
+}
+
+// NewConfigFileAuth returns a new AuthProvider that reads the API key from the "api_key" field of a JSON config file.
+// DefaultConfigFilePath is used if path is empty. The API key is kept in memory and the file is read again only when it
+// is modified.
+func NewConfigFileAuth(path string) AuthProvider {
+	return &configFileAuthProvider{path: path}
+}
+
+type configFileAuthProvider struct {
+	path string
+
+	// mu guards the API key read from the file and the modification time of the file when it was read
+	mu      sync.Mutex
+	apiKey  string
+	modTime time.Time
+	size    int64
+}
+
+type configFile struct {
+	APIKey string `json:"api_key"`
+}
+
+// Auth Provides the auth token header used in calling the APIs
+func (a *configFileAuthProvider) Auth() (string, error) {
+	path, err := a.configPath()
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		a.apiKey = ""
+		return "", fmt.Errorf("%w: %s does not exist", ErrNoCredentials, path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if a.apiKey != "" && info.ModTime().Equal(a.modTime) && info.Size() == a.size {
+		return a.apiKey, nil
+	}
+	a.apiKey = ""
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s does not exist", ErrNoCredentials, path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var config configFile
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return "", fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if config.APIKey == "" {
+		return "", fmt.Errorf("%w: %s has no api_key", ErrNoCredentials, path)
+	}
+
+	a.apiKey = config.APIKey
+	a.modTime = info.ModTime()
+	a.size = info.Size()
+
+	return config.APIKey, nil
+}
+
+func (a *configFileAuthProvider) configPath() (string, error) {
+	if a.path != "" {
+		return a.path, nil
+	}
+
+	return DefaultConfigFilePath()
+}
+
+func (a *configFileAuthProvider) String() string {
+	path, err := a.configPath()
+	if err != nil {
+		return "config file"
+	}
+
+	return "config file " + path
+}
+
+// ChainAuthError will be returned when none of the providers of a chain could authenticate. Errors holds the error of
+// each provider in order.
+type ChainAuthError struct {
+	Errors []error
+}
+
+func (e *ChainAuthError) Error() string {
+	if len(e.Errors) == 0 {
+		return ErrNoCredentials.Error() + ": no auth providers in the chain"
+	}
+
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return ErrNoCredentials.Error() + ", tried: " + strings.Join(messages, "; ")
+}
+
+// Is makes the error match ErrNoCredentials and the errors of the providers, e.g. ErrRequestSigningRequired
+func (e *ChainAuthError) Is(target error) bool {
+	if target == ErrNoCredentials {
+		return true
+	}
+
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewChainAuth returns a new AuthProvider that tries the given providers in order and uses the first one that can
+// authenticate. The chosen provider is remembered and used for the next requests until it fails, e.g. when its refresh
+// token is revoked, and then the chain is tried again. The providers implementing fmt.Stringer are named by it in the
+// errors.
+func NewChainAuth(providers ...AuthProvider) AuthProvider {
+	return &chainAuthProvider{providers: providers, selected: -1}
+}
+
+type chainAuthProvider struct {
+	providers []AuthProvider
+
+	// mu guards selected, the index of the chosen provider or -1 if none is chosen
+	mu       sync.Mutex
+	selected int
+}
+
+// Auth Provides the auth token header used in calling the APIs
+func (a *chainAuthProvider) Auth() (string, error) {
+	var token string
+	err := a.run(func(provider AuthProvider) error {
+		if _, ok := provider.(RequestSigner); ok {
+			return ErrRequestSigningRequired
+		}
+
+		var err error
+		token, err = provider.Auth()
+		if err == nil && token == "" {
+			return fmt.Errorf("%w: empty token", ErrNoCredentials)
+		}
+
+		return err
+	})
+
+	return token, err
+}
+
+// Sign authenticates the request with the chosen provider, which may sign the request or provide a bearer token
+func (a *chainAuthProvider) Sign(req *http.Request) error {
+	return a.run(func(provider AuthProvider) error {
+		return authorize(provider, req)
+	})
+}
+
+func (a *chainAuthProvider) run(fn func(provider AuthProvider) error) error {
+	a.mu.Lock()
+	selected := a.selected
+	a.mu.Unlock()
+
+	var selectedErr error
+	if selected >= 0 {
+		selectedErr = fn(a.providers[selected])
+		if selectedErr == nil {
+			return nil
+		}
+	}
+
+	chainErr := &ChainAuthError{}
+	for i, provider := range a.providers {
+		// The chosen provider which has just failed is not tried again
+		err := selectedErr
+		if i != selected {
+			err = fn(provider)
+		}
+
+		if err == nil {
+			a.mu.Lock()
+			if a.selected == selected {
+				a.selected = i
+			}
+			a.mu.Unlock()
+
+			return nil
+		}
+
+		chainErr.Errors = append(chainErr.Errors, fmt.Errorf("%s: %w", providerName(provider), err))
+	}
+
+	a.mu.Lock()
+	if a.selected == selected {
+		a.selected = -1
+	}
+	a.mu.Unlock()
+
+	return chainErr
+}
+
+func providerName(provider AuthProvider) string {
+	if stringer, ok := provider.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	return fmt.Sprintf("%T", provider)
+}
+
+// NewDefaultChainAuth returns a chain of the built-in providers: the DefaultAPIKeyEnv environment variable, the config
+// file at DefaultConfigFilePath and, if store is not nil, the stored OAuth token of the account.
+func NewDefaultChainAuth(store TokenStore, account string, refresh TokenRefreshFunc) AuthProvider {
+	providers := []AuthProvider{
+		NewEnvAuth(""),
+		NewConfigFileAuth(""),
+	}
+	if store != nil {
+		providers = append(providers, NewTokenStoreAuth(store, account, refresh))
+	}
+
+	return NewChainAuth(providers...)
+}
@@ -0,0 +1,252 @@
+package splitwise
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setEnv(t *testing.T, name, value string) {
+	previous, ok := os.LookupEnv(name)
+	if err := os.Setenv(name, value); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if ok {
+			_ = os.Setenv(name, previous)
+		} else {
+			_ = os.Unsetenv(name)
+		}
+	})
+}
+
+func TestEnvAuthProvider_Auth(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		setEnv(t, "SPLITWISE_TEST_API_KEY", "env-key")
+
+		token, err := NewEnvAuth("SPLITWISE_TEST_API_KEY").Auth()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "env-key" {
+			t.Fatalf("unexpected token %q", token)
+		}
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		setEnv(t, "SPLITWISE_TEST_API_KEY", "")
+
+		_, err := NewEnvAuth("SPLITWISE_TEST_API_KEY").Auth()
+		if !errors.Is(err, ErrNoCredentials) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
+
+func TestConfigFileAuthProvider_Auth(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("success", func(t *testing.T) {
+		path := filepath.Join(dir, "config.json")
+		if err := os.WriteFile(path, []byte(`{"api_key": "file-key"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		token, err := NewConfigFileAuth(path).Auth()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "file-key" {
+			t.Fatalf("unexpected token %q", token)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewConfigFileAuth(filepath.Join(dir, "missing.json")).Auth()
+		if !errors.Is(err, ErrNoCredentials) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		path := filepath.Join(dir, "empty.json")
+		if err := os.WriteFile(path, []byte(`{}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := NewConfigFileAuth(path).Auth()
+		if !errors.Is(err, ErrNoCredentials) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("cached key", func(t *testing.T) {
+		path := filepath.Join(dir, "cached.json")
+		if err := os.WriteFile(path, []byte(`{"api_key": "file-key"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		provider := NewConfigFileAuth(path).(*configFileAuthProvider)
+		if _, err := provider.Auth(); err != nil {
+			t.Fatal(err)
+		}
+
+		// The file is not read again while its modification time and size are the same
+		provider.apiKey = "cached-key"
+		token, err := provider.Auth()
+		if err != nil || token != "cached-key" {
+			t.Fatalf("unexpected token %q: %v", token, err)
+		}
+
+		if err := os.WriteFile(path, []byte(`{"api_key": "new-file-key"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		token, err = provider.Auth()
+		if err != nil || token != "new-file-key" {
+			t.Fatalf("unexpected token %q: %v", token, err)
+		}
+
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+		_, err = provider.Auth()
+		if !errors.Is(err, ErrNoCredentials) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
+
+func TestChainAuthProvider(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("first available provider", func(t *testing.T) {
+		setEnv(t, "SPLITWISE_TEST_API_KEY", "")
+		store := NewMemoryTokenStore()
+		if err := store.Save(context.Background(), "me", &Token{AccessToken: "stored-token"}); err != nil {
+			t.Fatal(err)
+		}
+
+		provider := NewChainAuth(
+			NewEnvAuth("SPLITWISE_TEST_API_KEY"),
+			NewConfigFileAuth(filepath.Join(dir, "missing.json")),
+			NewTokenStoreAuth(store, "me", nil),
+		)
+
+		token, err := provider.Auth()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "stored-token" {
+			t.Fatalf("unexpected token %q", token)
+		}
+
+		// The chosen provider is kept even if a previous one becomes available
+		setEnv(t, "SPLITWISE_TEST_API_KEY", "env-key")
+		token, err = provider.Auth()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "stored-token" {
+			t.Fatalf("unexpected token %q", token)
+		}
+	})
+
+	t.Run("chosen provider fails", func(t *testing.T) {
+		setEnv(t, "SPLITWISE_TEST_API_KEY", "env-key")
+		store := NewMemoryTokenStore()
+		if err := store.Save(context.Background(), "me", &Token{AccessToken: "stored-token"}); err != nil {
+			t.Fatal(err)
+		}
+
+		provider := NewChainAuth(
+			NewEnvAuth("SPLITWISE_TEST_API_KEY"),
+			NewTokenStoreAuth(store, "me", nil),
+		)
+
+		token, err := provider.Auth()
+		if err != nil || token != "env-key" {
+			t.Fatalf("unexpected token %q: %v", token, err)
+		}
+
+		// The next providers are tried again once the chosen one fails
+		setEnv(t, "SPLITWISE_TEST_API_KEY", "")
+		token, err = provider.Auth()
+		if err != nil || token != "stored-token" {
+			t.Fatalf("unexpected token %q: %v", token, err)
+		}
+
+		provider = NewChainAuth(NewEnvAuth("SPLITWISE_TEST_API_KEY"), NewEnvAuth("SPLITWISE_TEST_API_KEY_UNSET"))
+		setEnv(t, "SPLITWISE_TEST_API_KEY", "env-key")
+		if _, err := provider.Auth(); err != nil {
+			t.Fatal(err)
+		}
+
+		setEnv(t, "SPLITWISE_TEST_API_KEY", "")
+		_, err = provider.Auth()
+
+		var chainErr *ChainAuthError
+		if !errors.As(err, &chainErr) || len(chainErr.Errors) != 2 {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		setEnv(t, "SPLITWISE_TEST_API_KEY", "")
+		missing := filepath.Join(dir, "missing.json")
+
+		provider := NewChainAuth(
+			NewEnvAuth("SPLITWISE_TEST_API_KEY"),
+			NewConfigFileAuth(missing),
+			NewTokenStoreAuth(NewMemoryTokenStore(), "me", nil),
+		)
+
+		_, err := provider.Auth()
+		if !errors.Is(err, ErrNoCredentials) {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		var chainErr *ChainAuthError
+		if !errors.As(err, &chainErr) || len(chainErr.Errors) != 3 {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if !errors.Is(chainErr.Errors[2], ErrTokenNotFound) {
+			t.Fatalf("unexpected error %v", chainErr.Errors[2])
+		}
+
+		for _, source := range []string{"SPLITWISE_TEST_API_KEY", missing, `token store (account "me")`} {
+			if !strings.Contains(err.Error(), source) {
+				t.Fatalf("error %q does not mention %s", err, source)
+			}
+		}
+	})
+
+	t.Run("signing provider", func(t *testing.T) {
+		provider := NewChainAuth(
+			NewEnvAuth("SPLITWISE_TEST_API_KEY_UNSET"),
+			NewOAuth1Auth("consumer-key", "consumer-secret", "token", "token-secret"),
+		)
+
+		req, err := http.NewRequest(http.MethodGet, "https://example.com/api", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = authorize(provider, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "OAuth ") {
+			t.Fatalf("unexpected Authorization header %q", req.Header.Get("Authorization"))
+		}
+
+		_, err = provider.Auth()
+		if !errors.Is(err, ErrRequestSigningRequired) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
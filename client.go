@@ -1,63 +1,389 @@
 package splitwise
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 )
 
 type Client interface {
+	// Do calls an API by its path relative to the API version, e.g. "get_groups", with the given query and body and
+	// decodes the JSON response into out, if it is not nil. It is useful for the APIs which are not covered by the
+	// other methods. The body is encoded as JSON, or form encoded if it is url.Values.
+	Do(ctx context.Context, method, path string, query url.Values, body interface{}, out interface{}) error
+
 	Users
 	Groups
 	Friends
 	Expenses
+	Comments
+	Notifications
 	Currencies
 	Categories
 }
 
 const (
 	ServerAddress = "https://secure.splitwise.com"
+
+	// DefaultAPIVersion is the version of the APIs that the client calls by default
+	DefaultAPIVersion = "v3.0"
+
+	// maxErrorBodySize limits the size of the error response bodies kept in APIError
+	maxErrorBodySize = 1 << 20
 )
 
-// NewClient returns a new Client with the given AuthProvider
-func NewClient(authProvider AuthProvider) Client {
-	return &client{
+// ClientOption configures the Client returned by NewClient
+type ClientOption func(c *client)
+
+// WithHTTPClient sets the HTTP client used for sending the requests, e.g. to use a proxy. http.DefaultClient is used
+// by default, or if httpClient is nil.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *client) {
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+
+		c.client = httpClient
+	}
+}
+
+// WithBaseURL sets the address of the service, ServerAddress by default
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests to identify the application
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithAPIVersion sets the version of the APIs, DefaultAPIVersion by default
+func WithAPIVersion(version string) ClientOption {
+	return func(c *client) {
+		c.apiVersion = version
+	}
+}
+
+// WithTimeout sets the time limit of each request, including reading the response body. It does not modify the HTTP
+// client given to WithHTTPClient.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *client) {
+		c.timeout = timeout
+	}
+}
+
+// NewClient returns a new Client with the given AuthProvider and options
+func NewClient(authProvider AuthProvider, opts ...ClientOption) Client {
+	c := &client{
 		AuthProvider: authProvider,
 		baseURL:      ServerAddress,
+		apiVersion:   DefaultAPIVersion,
 		client:       http.DefaultClient,
 	}
+
+	retryPolicy := DefaultRetryPolicy()
+	c.retryPolicy = &retryPolicy
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.timeout > 0 {
+		httpClient := *c.client
+		httpClient.Timeout = c.timeout
+		c.client = &httpClient
+	}
+
+	return c
 }
 
 type client struct {
 	AuthProvider
-	baseURL string
-	client  *http.Client
+	baseURL    string
+	apiVersion string
+	userAgent  string
+	timeout    time.Duration
+	client     *http.Client
+
+	// retryPolicy is nil if the requests should not be retried
+	retryPolicy *RetryPolicy
+
+	// rateLimiter is nil if the requests are not limited
+	rateLimiter *RateLimiter
+
+	middlewares []Middleware
+
+	// tracer is nil if the calls are not traced
+	tracer Tracer
+
+	// metrics is nil if the calls are not recorded
+	metrics *Metrics
 }
 
+// endpoint returns the URL of an API by its path, e.g. "get_groups"
+func (c client) endpoint(path string) string {
+	version := c.apiVersion
+	if version == "" {
+		version = DefaultAPIVersion
+	}
+
+	return c.baseURL + "/api/" + version + "/" + path
+}
+
+// do authorizes the request and sends it, retrying it according to the retry policy of the client
+func (c client) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	policy := c.retryPolicy
+	retryable := policy.allows(req)
+
+	stats := statsFromContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		if stats != nil {
+			stats.attempts = attempt
+		}
+
+		attemptReq := req
+		if attempt > 1 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		if c.userAgent != "" {
+			attemptReq.Header.Set("User-Agent", c.userAgent)
+		}
+
+		err := c.rateLimiter.Wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		err = authorize(c.AuthProvider, attemptReq)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := c.client.Do(attemptReq)
+		if !retryable || attempt >= policy.MaxAttempts {
+			return res, err
+		}
+
+		if err != nil && !policy.retryableError(ctx, err) {
+			return nil, err
+		}
+		if err == nil && !policy.retryableStatus(res.StatusCode) {
+			return res, nil
+		}
+
+		delay, ok := policy.delay(attempt, res, time.Now())
+		if !ok || !fitsDeadline(ctx, delay) {
+			return res, err
+		}
+
+		if res != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+			_ = res.Body.Close()
+		}
+
+		err = sleep(ctx, delay)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// checkError returns an *APIError if the response has an error status code
 func (c client) checkError(res *http.Response) error {
-	switch {
-	case res.StatusCode/100 == 1:
-		return nil
-	case res.StatusCode/100 == 2:
+	if res.StatusCode < 400 {
 		return nil
-	case res.StatusCode/100 == 3:
-		return nil
-	case res.StatusCode == 401:
-		return ErrInvalidToken
-	case res.StatusCode == 403:
-		return ErrPermissionDenied
-	case res.StatusCode == 404:
-		return ErrRecordNotFound
-	case res.StatusCode == 500:
-		return ErrSplitwiseServer
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+	if err != nil {
+		return err
+	}
+
+	return newAPIError(res, body)
+}
+
+// rawRequestBody is a request body which is already encoded, e.g. a multipart form
+type rawRequestBody struct {
+	body        io.Reader
+	contentType string
+}
+
+// newRequestBody encodes the body of a request. url.Values are form encoded, a rawRequestBody is sent as it is and the
+// other values are encoded as JSON.
+func newRequestBody(body interface{}) (io.Reader, string, error) {
+	switch body := body.(type) {
+	case nil:
+		return nil, "", nil
+	case url.Values:
+		return strings.NewReader(body.Encode()), "application/x-www-form-urlencoded", nil
+	case rawRequestBody:
+		return body.body, body.contentType, nil
 	default:
-		// This case normally should not be happened
-		var response interface{}
-		err := json.NewDecoder(res.Body).Decode(&response)
+		rawBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return bytes.NewReader(rawBody), "application/json", nil
+	}
+}
+
+type envelopeResponse struct {
+	Errors ResponseErrors `json:"errors"`
+}
+
+// Do calls an API by its path relative to the API version, e.g. "get_groups", with the given query and body and
+// decodes the JSON response into out, if it is not nil. The body is encoded as JSON, or form encoded if it is
+// url.Values. The errors reported in the response of a request other than GET are returned as a *ValidationError.
+func (c client) Do(ctx context.Context, method, path string, query url.Values, body interface{}, out interface{}) error {
+	return c.call(ctx, "Do", method, strings.TrimPrefix(path, "/"), query, body, out)
+}
+
+// callStats collects what happens during an API call, to be reported by the tracer
+type callStats struct {
+	attempts   int
+	statusCode int
+}
+
+type callStatsKey struct{}
+
+// statsFromContext returns the stats of the API call that ctx belongs to, or nil
+func statsFromContext(ctx context.Context) *callStats {
+	stats, _ := ctx.Value(callStatsKey{}).(*callStats)
+
+	return stats
+}
+
+// retries returns the number of the attempts after the first one
+func (s *callStats) retries() int {
+	if s.attempts < 1 {
+		return 0
+	}
+
+	return s.attempts - 1
+}
+
+// call sends a request to an API on behalf of an operation and decodes its response. All the API calls go through it.
+// out is decoded even if a *ValidationError is returned, so that the callers can inspect the whole response.
+func (c client) call(ctx context.Context, op, method, path string, query url.Values, body, out interface{}) (err error) {
+	stats := &callStats{}
+	ctx = context.WithValue(withOperationName(ctx, op), callStatsKey{}, stats)
+
+	if c.metrics != nil {
+		start := time.Now()
+		defer func() {
+			c.metrics.observe(op, time.Since(start), err)
+		}()
+	}
+
+	if c.tracer != nil {
+		var span Span
+		ctx, span = c.tracer.StartSpan(ctx, op)
+		span.SetAttr(AttrOperation, op)
+		span.SetAttr(AttrHTTPMethod, method)
+		defer func() {
+			if stats.statusCode != 0 {
+				span.SetAttr(AttrHTTPStatusCode, stats.statusCode)
+			}
+			span.SetAttr(AttrRetries, stats.retries())
+			span.End(err)
+		}()
+	}
+
+	return c.send(ctx, method, path, query, body, out)
+}
+
+// send builds the request, sends it through the middlewares and decodes the response
+func (c client) send(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	endpoint := c.endpoint(path)
+	if len(query) != 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	reqBody, contentType, err := newRequestBody(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	res, err := c.handler()(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if stats := statsFromContext(ctx); stats != nil {
+		stats.statusCode = res.StatusCode
+	}
+
+	err = c.checkError(res)
+	if err != nil {
+		return err
+	}
+
+	rawResponse, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if out != nil && len(rawResponse) != 0 {
+		err = json.Unmarshal(rawResponse, out)
 		if err != nil {
 			return err
 		}
+	}
 
-		return fmt.Errorf("unknown API status code: %d - payload: %+v", res.StatusCode, response)
+	// 200 OK does not indicate a successful mutation, the errors field of the response should be empty as well
+	if method != http.MethodGet {
+		var envelope envelopeResponse
+		if json.Unmarshal(rawResponse, &envelope) == nil && len(envelope.Errors) != 0 {
+			return &ValidationError{Errors: envelope.Errors}
+		}
 	}
+
+	return nil
+}
+
+type operationResponse struct {
+	Success bool `json:"success"`
+}
+
+// operation calls an endpoint which only reports whether the operation was successful. path is relative to the API
+// version, e.g. "delete_group/1".
+func (c client) operation(ctx context.Context, op, path string) error {
+	var response operationResponse
+	err := c.call(ctx, op, http.MethodPost, path, nil, nil, &response)
+	if err != nil {
+		return err
+	}
+
+	return checkOperationResult(response.Success, nil)
 }
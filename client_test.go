@@ -0,0 +1,161 @@
+package splitwise
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		c := NewClient(NewAPIKeyAuth("api-key")).(*client)
+
+		if c.baseURL != ServerAddress || c.client != http.DefaultClient {
+			t.Fatalf("unexpected client %+v", c)
+		}
+		if url := c.endpoint("get_groups"); url != ServerAddress+"/api/v3.0/get_groups" {
+			t.Fatalf("unexpected endpoint %s", url)
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != "/api/v4.0/get_currencies" {
+				t.Errorf("unexpected path %s", req.URL.Path)
+			}
+			if req.Header.Get("User-Agent") != "my-app/1.0" {
+				t.Errorf("unexpected User-Agent %q", req.Header.Get("User-Agent"))
+			}
+
+			_, _ = rw.Write([]byte(`{"currencies": [{"currency_code": "USD", "unit": "$"}]}`))
+		}))
+		// Close the server when test finishes
+		defer server.Close()
+
+		httpClient := &http.Client{}
+		c := NewClient(
+			NewAPIKeyAuth("api-key"),
+			WithHTTPClient(httpClient),
+			WithBaseURL(server.URL+"/"),
+			WithUserAgent("my-app/1.0"),
+			WithAPIVersion("v4.0"),
+			WithTimeout(5*time.Second),
+		)
+
+		currencies, err := c.Currencies(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(currencies) != 1 || currencies[0].CurrencyCode != "USD" {
+			t.Fatalf("unexpected currencies %+v", currencies)
+		}
+
+		if timeout := c.(*client).client.Timeout; timeout != 5*time.Second {
+			t.Fatalf("unexpected timeout %s", timeout)
+		}
+		if httpClient.Timeout != 0 {
+			t.Fatal("the given HTTP client is modified")
+		}
+	})
+
+	t.Run("nil HTTP client", func(t *testing.T) {
+		c := NewClient(NewAPIKeyAuth("api-key"), WithHTTPClient(nil), WithTimeout(5*time.Second)).(*client)
+
+		if c.client == nil || c.client == http.DefaultClient || c.client.Timeout != 5*time.Second {
+			t.Fatalf("unexpected HTTP client %+v", c.client)
+		}
+		if http.DefaultClient.Timeout != 0 {
+			t.Fatal("http.DefaultClient is modified")
+		}
+	})
+}
+
+func TestClient_Do(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodGet || req.URL.String() != "/api/v3.0/get_main_data?no_expenses=1" {
+				t.Errorf("unexpected request %s %s", req.Method, req.URL)
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"user": {"id": 1313}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		var response struct {
+			User User `json:"user"`
+		}
+		err := c.Do(context.Background(), http.MethodGet, "/get_main_data", url.Values{"no_expenses": {"1"}}, nil, &response)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if response.User.ID != 1313 {
+			t.Error("invalid user ID")
+		}
+	})
+
+	t.Run("form body", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" || req.FormValue("id") != "7" {
+				t.Errorf("unexpected request body")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"success": true}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		err := c.Do(context.Background(), http.MethodPost, "do_something", nil, url.Values{"id": {"7"}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			if req.Header.Get("Content-Type") != "application/json" || string(body) != `{"name":""}` {
+				t.Errorf("unexpected request body %s", body)
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"group": null, "errors": {"name": ["can't be blank"]}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		err := c.Do(context.Background(), http.MethodPost, "create_group", nil, map[string]string{"name": ""}, nil)
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected a validation error, got %v", err)
+		}
+	})
+}
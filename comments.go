@@ -2,19 +2,109 @@ package splitwise
 
 import (
 	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 // Comments contains methods to access and modify comments on expenses
 type Comments interface {
-	// Comments is responsible to get expense comments
-	Comments(ctx context.Context) ([]Comment, error)
+	// Comments returns the comments of an expense identified by expenseID
+	Comments(ctx context.Context, expenseID uint64) ([]Comment, error)
 
-	// CreateComment creates a comment for an expense
-	CreateComment(ctx context.Context, dto *CreateCommentDTO) (*Comment, error)
+	// CreateComment creates a comment with the given content for an expense and returns the result
+	CreateComment(ctx context.Context, expenseID uint64, content string) (*Comment, error)
+
+	// DeleteComment deletes a comment by its ID and returns the deleted comment
+	DeleteComment(ctx context.Context, id uint64) (*Comment, error)
 }
 
+// CommentType shows who has written a comment
+type CommentType string
+
+const (
+	// CommentTypeSystem is used for the comments generated by Splitwise, e.g. on updating an expense
+	CommentTypeSystem CommentType = "System"
+
+	// CommentTypeUser is used for the comments written by users
+	CommentTypeUser CommentType = "User"
+)
+
+// CommentRelationType shows the kind of resource that a comment is attached to
+type CommentRelationType string
+
+const (
+	// CommentRelationTypeExpense is used for the comments attached to an expense
+	CommentRelationTypeExpense CommentRelationType = "ExpenseComment"
+)
+
 type Comment struct {
+	ID           uint64              `json:"id"`
+	Content      string              `json:"content"`
+	CommentType  CommentType         `json:"comment_type"`
+	RelationType CommentRelationType `json:"relation_type"`
+	RelationID   uint64              `json:"relation_id"`
+	CreatedAt    time.Time           `json:"created_at"`
+	DeletedAt    *time.Time          `json:"deleted_at"`
+	User         *CommentUser        `json:"user"`
+}
+
+// CommentUser is the author of a comment. It is nil for the system generated comments.
+type CommentUser struct {
+	ID        uint64 `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Picture   struct {
+		Medium string `json:"medium"`
+	} `json:"picture"`
+}
+
+type commentsResponse struct {
+	Comments []Comment `json:"comments"`
 }
 
-type CreateCommentDTO struct {
+// Comments returns the comments of an expense identified by expenseID
+func (c client) Comments(ctx context.Context, expenseID uint64) ([]Comment, error) {
+	query := url.Values{}
+	query.Set("expense_id", strconv.FormatUint(expenseID, 10))
+
+	var response commentsResponse
+	err := c.call(ctx, "Comments", http.MethodGet, "get_comments", query, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Comments, nil
+}
+
+type commentResponse struct {
+	Comment Comment `json:"comment"`
+}
+
+// CreateComment creates a comment with the given content for an expense and returns the result
+func (c client) CreateComment(ctx context.Context, expenseID uint64, content string) (*Comment, error) {
+	body := map[string]interface{}{
+		"expense_id": expenseID,
+		"content":    content,
+	}
+
+	var response commentResponse
+	err := c.call(ctx, "CreateComment", http.MethodPost, "create_comment", nil, body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.Comment, nil
+}
+
+// DeleteComment deletes a comment by its ID and returns the deleted comment
+func (c client) DeleteComment(ctx context.Context, id uint64) (*Comment, error) {
+	var response commentResponse
+	err := c.call(ctx, "DeleteComment", http.MethodPost, "delete_comment/"+strconv.FormatUint(id, 10), nil, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.Comment, nil
 }
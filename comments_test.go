@@ -1 +1,214 @@
 package splitwise
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Comments(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/get_comments?expense_id=855870953" {
+				t.Error("invalid URL request")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"comments": [
+					{
+						"id": 79800950,
+						"content": "John D. updated this transaction: - The cost changed from $6.99 to $8.99",
+						"comment_type": "System",
+						"relation_type": "ExpenseComment",
+						"relation_id": 855870953,
+						"created_at": "2019-08-24T14:15:22Z",
+						"deleted_at": null,
+						"user": null
+					},
+					{
+						"id": 79800951,
+						"content": "Was it really that expensive?",
+						"comment_type": "User",
+						"relation_type": "ExpenseComment",
+						"relation_id": 855870953,
+						"created_at": "2019-08-24T15:15:22Z",
+						"deleted_at": null,
+						"user": {
+							"id": 491923,
+							"first_name": "Jane",
+							"last_name": "Doe",
+							"picture": {
+								"medium": "image_url"
+							}
+						}
+					}
+				]
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		comments, err := c.Comments(context.Background(), 855870953)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(comments) != 2 {
+			t.Fatalf("expected 2 comments, got %d", len(comments))
+		}
+
+		if comments[0].CommentType != CommentTypeSystem || comments[0].User != nil {
+			t.Error("invalid system comment")
+		}
+
+		if comments[1].CommentType != CommentTypeUser || comments[1].User == nil || comments[1].User.ID != 491923 {
+			t.Error("invalid user comment")
+		}
+	})
+}
+
+func TestClient_CreateComment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/create_comment" {
+				t.Error("invalid URL request")
+			}
+
+			reqBody, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fail()
+			}
+
+			expectedReqBody := `{"content":"Was it really that expensive?","expense_id":855870953}`
+			if string(reqBody) != expectedReqBody {
+				t.FailNow()
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"comment": {
+					"id": 79800951,
+					"content": "Was it really that expensive?",
+					"comment_type": "User",
+					"relation_type": "ExpenseComment",
+					"relation_id": 855870953,
+					"created_at": "2019-08-24T15:15:22Z",
+					"deleted_at": null,
+					"user": {
+						"id": 491923,
+						"first_name": "Jane",
+						"last_name": "Doe",
+						"picture": {
+							"medium": "image_url"
+						}
+					}
+				}
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		comment, err := c.CreateComment(context.Background(), 855870953, "Was it really that expensive?")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if comment.ID != 79800951 {
+			t.Error("invalid comment ID")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"comment": null, "errors": {"content": ["can't be blank"]}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		_, err := c.CreateComment(context.Background(), 855870953, "")
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected a validation error, got %v", err)
+		}
+
+		if validationErr.Error() != "validation failed: content: can't be blank" {
+			t.Errorf("unexpected error message: %s", validationErr.Error())
+		}
+	})
+}
+
+func TestClient_DeleteComment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/delete_comment/79800951" {
+				t.Error("invalid URL request")
+			}
+
+			if req.Method != http.MethodPost {
+				t.Error("invalid request method")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"comment": {
+					"id": 79800951,
+					"content": "Was it really that expensive?",
+					"comment_type": "User",
+					"relation_type": "ExpenseComment",
+					"relation_id": 855870953,
+					"created_at": "2019-08-24T15:15:22Z",
+					"deleted_at": "2019-08-25T15:15:22Z",
+					"user": {
+						"id": 491923,
+						"first_name": "Jane",
+						"last_name": "Doe",
+						"picture": {
+							"medium": "image_url"
+						}
+					}
+				}
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		comment, err := c.DeleteComment(context.Background(), 79800951)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if comment.DeletedAt == nil {
+			t.Error("expected deleted_at to be set")
+		}
+	})
+}
@@ -1,7 +1,12 @@
 package splitwise
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
 )
 
 var (
@@ -16,4 +21,259 @@ var (
 
 	// ErrSplitwiseServer will be returned on 500 internal server errors
 	ErrSplitwiseServer = errors.New("splitwise internal server error")
+
+	// ErrRateLimited will be returned in case of receiving 429 from the service
+	ErrRateLimited = errors.New("too many requests")
+
+	// ErrOperationFailed will be returned when the service reports an unsuccessful operation without giving a reason
+	ErrOperationFailed = errors.New("operation was not successful")
+
+	// ErrNonZeroBalance will be returned when a user can not be removed from a group because of their non-zero balance
+	ErrNonZeroBalance = errors.New("user has a non-zero balance")
+
+	// ErrSamePayerAndPayee will be returned on recording a payment from a user to themselves
+	ErrSamePayerAndPayee = errors.New("payer and payee of a payment must be different users")
+
+	// ErrTokenExpired will be returned when a token is expired and there is no way to refresh it
+	ErrTokenExpired = errors.New("token is expired and can not be refreshed")
+
+	// ErrRequestSigningRequired will be returned by the AuthProviders which can only authenticate by signing each
+	// request and can not provide a bearer token
+	ErrRequestSigningRequired = errors.New("auth provider requires signing each request")
+
+	// ErrTokenNotFound will be returned by the TokenStores when there is no token for an account
+	ErrTokenNotFound = errors.New("token not found")
+
+	// ErrTokenStoreDecryption will be returned when the tokens file can not be decrypted, e.g. by a wrong passphrase
+	ErrTokenStoreDecryption = errors.New("can not decrypt the token store")
+
+	// ErrLoginStateMismatch is reported to the OAuth callback requests whose state does not match the one that was sent.
+	// LoginWithBrowser rejects these requests and keeps waiting for the right one.
+	ErrLoginStateMismatch = errors.New("oauth2: state of the callback does not match")
+
+	// ErrReceiptNoReader will be returned when a receipt attached to an expense has no reader to read the image from
+	ErrReceiptNoReader = errors.New("receipt has no reader")
+
+	// ErrNoCredentials will be returned when an AuthProvider can not find its credentials, e.g. an unset environment
+	// variable
+	ErrNoCredentials = errors.New("no credentials found")
 )
+
+// ResponseErrors holds the errors reported in the body of a response. General errors are kept under the "base" key
+// and the errors of a specific field are kept under the name of that field.
+type ResponseErrors map[string][]string
+
+// UnmarshalJSON accepts the different shapes that the service uses for the errors field: an object of messages or
+// lists of messages, a list of messages or an empty list.
+func (e *ResponseErrors) UnmarshalJSON(data []byte) error {
+	result := ResponseErrors{}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		if len(list) != 0 {
+			result["base"] = list
+		}
+		*e = result
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for key, raw := range fields {
+		var messages []string
+		if err := json.Unmarshal(raw, &messages); err == nil {
+			if len(messages) != 0 {
+				result[key] = messages
+			}
+			continue
+		}
+
+		var message string
+		if err := json.Unmarshal(raw, &message); err == nil {
+			if message != "" {
+				result[key] = []string{message}
+			}
+			continue
+		}
+
+		result[key] = []string{string(raw)}
+	}
+
+	*e = result
+	return nil
+}
+
+// Base returns the general errors that are not related to a specific field
+func (e ResponseErrors) Base() []string {
+	return e["base"]
+}
+
+func (e ResponseErrors) String() string {
+	keys := e.sortedKeys()
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		messages := strings.Join(e[key], ", ")
+		if key == "base" {
+			parts = append(parts, messages)
+		} else {
+			parts = append(parts, key+": "+messages)
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func (e ResponseErrors) sortedKeys() []string {
+	keys := make([]string, 0, len(e))
+	for key := range e {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ValidationError will be returned when the service rejects an operation and reports the reasons in the errors field
+// of the response
+type ValidationError struct {
+	Errors ResponseErrors
+
+	// cause is a well known error that the reported errors are recognized as, if any
+	cause error
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed: " + e.Errors.String()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.cause
+}
+
+// contains reports whether any of the reported messages contains the given phrase, ignoring the case
+func (e *ValidationError) contains(phrase string) bool {
+	phrase = strings.ToLower(phrase)
+	for _, messages := range e.Errors {
+		for _, message := range messages {
+			if strings.Contains(strings.ToLower(message), phrase) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkOperationResult converts the success flag and the errors reported in the body of a response into an error
+func checkOperationResult(success bool, responseErrors ResponseErrors) error {
+	if len(responseErrors) != 0 {
+		return &ValidationError{Errors: responseErrors}
+	}
+
+	if !success {
+		return ErrOperationFailed
+	}
+
+	return nil
+}
+
+// APIError will be returned when the service responds with an error status code. It matches ErrInvalidToken,
+// ErrPermissionDenied, ErrRecordNotFound, ErrRateLimited and ErrSplitwiseServer by its status code using errors.Is.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+
+	// Method and Endpoint are the HTTP method and the URL path of the request
+	Method   string
+	Endpoint string
+
+	// RequestID is the X-Request-Id header of the response, if any
+	RequestID string
+
+	// Body is the raw body of the response
+	Body []byte
+
+	// Errors are the base and per-field messages reported in the body, if it could be parsed
+	Errors ResponseErrors
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("unknown API status code: %d", e.StatusCode)
+	if sentinel := e.sentinel(); sentinel != nil {
+		message = sentinel.Error()
+	}
+
+	if len(e.Errors) != 0 {
+		message += ": " + e.Errors.String()
+	} else if e.sentinel() == nil && len(e.Body) != 0 {
+		message += " - payload: " + string(e.Body)
+	}
+
+	if e.Endpoint != "" {
+		message += fmt.Sprintf(" (%s %s)", e.Method, e.Endpoint)
+	}
+
+	return message
+}
+
+// Is makes the error match the sentinel error of its status code
+func (e *APIError) Is(target error) bool {
+	sentinel := e.sentinel()
+
+	return sentinel != nil && sentinel == target
+}
+
+func (e *APIError) sentinel() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrInvalidToken
+	case e.StatusCode == http.StatusForbidden:
+		return ErrPermissionDenied
+	case e.StatusCode == http.StatusNotFound:
+		return ErrRecordNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode/100 == 5:
+		return ErrSplitwiseServer
+	default:
+		return nil
+	}
+}
+
+// errorResponse is the body of the error responses, which reports the errors either in the errors field or as a single
+// message in the error field
+type errorResponse struct {
+	Errors ResponseErrors `json:"errors"`
+	Error  string         `json:"error"`
+}
+
+// newAPIError builds an APIError from the status, headers and body of a response
+func newAPIError(res *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		RequestID:  res.Header.Get("X-Request-Id"),
+		Body:       body,
+	}
+
+	if res.Request != nil {
+		apiErr.Method = res.Request.Method
+		apiErr.Endpoint = res.Request.URL.Path
+	}
+
+	var response errorResponse
+	if err := json.Unmarshal(body, &response); err == nil {
+		apiErr.Errors = response.Errors
+		if response.Error != "" {
+			if apiErr.Errors == nil {
+				apiErr.Errors = ResponseErrors{}
+			}
+			apiErr.Errors["base"] = append(apiErr.Errors["base"], response.Error)
+		}
+	}
+
+	return apiErr
+}
@@ -0,0 +1,133 @@
+package splitwise
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseErrors_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		expected string
+	}{
+		{name: "empty object", payload: `{}`, expected: ""},
+		{name: "empty list", payload: `[]`, expected: ""},
+		{name: "list", payload: `["first", "second"]`, expected: "first, second"},
+		{name: "object of lists", payload: `{"base": ["failed"], "cost": ["is invalid"]}`, expected: "failed; cost: is invalid"},
+		{name: "object of strings", payload: `{"base": "failed"}`, expected: "failed"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var responseErrors ResponseErrors
+			err := json.Unmarshal([]byte(test.payload), &responseErrors)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if responseErrors.String() != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, responseErrors.String())
+			}
+		})
+	}
+}
+
+func TestClient_checkError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		sentinel   error
+		errors     string
+		message    string
+	}{
+		{
+			name:       "invalid token",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"error": "Invalid API request: you are not logged in"}`,
+			sentinel:   ErrInvalidToken,
+			errors:     "Invalid API request: you are not logged in",
+			message:    "invalid token: Invalid API request: you are not logged in (GET /api/v3.0/get_currencies)",
+		},
+		{
+			name:       "not found",
+			statusCode: http.StatusNotFound,
+			body:       `{"errors": {"base": ["Record not found"]}}`,
+			sentinel:   ErrRecordNotFound,
+			errors:     "Record not found",
+			message:    "invalid API Request: record not found: Record not found (GET /api/v3.0/get_currencies)",
+		},
+		{
+			name:       "validation",
+			statusCode: http.StatusUnprocessableEntity,
+			body:       `{"errors": {"cost": ["is invalid"]}}`,
+			errors:     "cost: is invalid",
+			message:    "unknown API status code: 422: cost: is invalid (GET /api/v3.0/get_currencies)",
+		},
+		{
+			name:       "rate limited",
+			statusCode: http.StatusTooManyRequests,
+			body:       `Too many requests`,
+			sentinel:   ErrRateLimited,
+			message:    "too many requests (GET /api/v3.0/get_currencies)",
+		},
+		{
+			name:       "server",
+			statusCode: http.StatusBadGateway,
+			body:       `<html></html>`,
+			sentinel:   ErrSplitwiseServer,
+			message:    "splitwise internal server error (GET /api/v3.0/get_currencies)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Start a local HTTP server
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("X-Request-Id", "request-id")
+				rw.WriteHeader(test.statusCode)
+				_, _ = rw.Write([]byte(test.body))
+			}))
+			// Close the server when test finishes
+			defer server.Close()
+
+			c := &client{
+				AuthProvider: NewAPIKeyAuth("api-key"),
+				baseURL:      server.URL,
+				client:       http.DefaultClient,
+			}
+
+			_, err := c.Currencies(context.Background())
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if apiErr.StatusCode != test.statusCode || apiErr.Method != http.MethodGet ||
+				apiErr.Endpoint != "/api/v3.0/get_currencies" || apiErr.RequestID != "request-id" ||
+				string(apiErr.Body) != test.body {
+				t.Errorf("unexpected error %+v", apiErr)
+			}
+
+			if apiErr.Errors.String() != test.errors {
+				t.Errorf("expected errors %q, got %q", test.errors, apiErr.Errors.String())
+			}
+
+			if err.Error() != test.message {
+				t.Errorf("expected message %q, got %q", test.message, err.Error())
+			}
+
+			for _, sentinel := range []error{ErrInvalidToken, ErrPermissionDenied, ErrRecordNotFound, ErrRateLimited, ErrSplitwiseServer} {
+				if errors.Is(err, sentinel) != (sentinel == test.sentinel) {
+					t.Errorf("errors.Is(err, %v) = %t", sentinel, errors.Is(err, sentinel))
+				}
+			}
+		})
+	}
+}
@@ -5,15 +5,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
+	"time"
 )
 
 // Expenses contains method to work with expense resource
 type Expenses interface {
-	// Expenses returns current user's expenses
-	Expenses(ctx context.Context) ([]ExpenseResponse, error)
+	// Expenses returns current user's expenses. Without any option, the default page of the service is returned.
+	Expenses(ctx context.Context, opts ...ExpensesOption) ([]ExpenseResponse, error)
 
 	// ExpenseByID returns info about an expense choose by id
 	ExpenseByID(ctx context.Context, id uint64) (ExpenseResponse, error)
@@ -27,9 +33,31 @@ type Expenses interface {
 	// of the following:
 	//email, first_name, and last_name
 	//user_id
-	//Note: 200 OK does not indicate a successful response. The operation was successful only if errors is empty.
-	CreateExpenseSplitEqually(ctx context.Context, expense ExpenseSplitEqually) ([]Expense, error)
-	CreateExpenseByShare(ctx context.Context, expense Expense, usersShares []UserShare) ([]Expense, error)
+	//Note: 200 OK does not indicate a successful response. The errors reported in the response are returned as a
+	// *ValidationError.
+	//The extra fields are sent along with the expense, e.g. ExpenseReceiptField to attach a receipt image.
+	CreateExpenseSplitEqually(ctx context.Context, expense ExpenseSplitEqually, fields ...ExpenseField) ([]Expense, error)
+	CreateExpenseByShare(ctx context.Context, expense Expense, usersShares []UserShare, fields ...ExpenseField) ([]Expense, error)
+
+	// UpdateExpense updates the given fields of an expense. If usersShares is not empty, the shares of the expense are
+	// replaced by them, in the same way as CreateExpenseByShare.
+	//Note: 200 OK does not indicate a successful response. The errors reported in the response are returned as a
+	// *ValidationError.
+	UpdateExpense(ctx context.Context, id uint64, usersShares []UserShare, fields ...ExpenseField) ([]ExpenseResponse, error)
+
+	// DeleteExpense deletes an expense by its ID
+	DeleteExpense(ctx context.Context, id uint64) error
+
+	// UndeleteExpense restores a deleted expense
+	UndeleteExpense(ctx context.Context, id uint64) error
+
+	// ParseSentence builds an expense from a sentence in natural language like "I paid $40 for dinner with Alice". The
+	// expense is created only if opts.Autosave is set, otherwise it is just proposed.
+	ParseSentence(ctx context.Context, input string, opts ParseSentenceOptions) (*ParsedSentence, error)
+
+	// RecordPayment records a payment of amount in the given currency from a user to another one, e.g. when settling
+	// up. groupID can be zero for the payments outside of groups.
+	RecordPayment(ctx context.Context, from, to uint64, amount, currency string, groupID uint64) (*Payment, error)
 }
 
 type ActionBy struct {
@@ -111,16 +139,7 @@ type ExpenseResponse struct {
 		OwedShare  string `json:"owed_share"`
 		NetBalance string `json:"net_balance"`
 	} `json:"users"`
-	Comments []struct {
-		Id           uint32 `json:"id"`
-		Content      string `json:"content"`
-		CommentType  string `json:"comment_type"`
-		RelationType string `json:"relation_type"`
-		RelationId   uint32 `json:"relation_id"`
-		CreatedAt    string `json:"created_at"`
-		DeletedAt    string `json:"deleted_at"`
-		User         string `json:"user"`
-	} `json:"comments"`
+	Comments []Comment `json:"comments"`
 }
 
 type createExpenseResponse struct {
@@ -131,42 +150,14 @@ type expensesResponse struct {
 	Expenses []ExpenseResponse `json:"expenses"`
 }
 
-func (c client) CreateExpenseSplitEqually(ctx context.Context, expense ExpenseSplitEqually) ([]Expense, error) {
-	url := c.baseURL + "/api/v3.0/create_expense"
-
-	body, err := json.Marshal(expense)
-	if err != nil {
-		return nil, err
-	}
-
-	token, err := c.AuthProvider.Auth()
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Content-Type", "application/json")
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
-
-	err = c.checkError(res)
+func (c client) CreateExpenseSplitEqually(ctx context.Context, expense ExpenseSplitEqually, fields ...ExpenseField) ([]Expense, error) {
+	body, err := newExpenseRequestBody(expense, fields)
 	if err != nil {
 		return nil, err
 	}
 
 	var response createExpenseResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
-
+	err = c.call(ctx, "CreateExpenseSplitEqually", http.MethodPost, "create_expense", nil, body, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -174,9 +165,7 @@ func (c client) CreateExpenseSplitEqually(ctx context.Context, expense ExpenseSp
 	return response.Expenses, nil
 }
 
-func (c client) CreateExpenseByShare(ctx context.Context, expense Expense, usersShares []UserShare) ([]Expense, error) {
-	url := c.baseURL + "/api/v3.0/create_expense"
-
+func (c client) CreateExpenseByShare(ctx context.Context, expense Expense, usersShares []UserShare, fields ...ExpenseField) ([]Expense, error) {
 	// Prepare to merge expense and the user shares on the same struct
 	var unmerged []interface{}
 	unmerged = append(unmerged, expense)
@@ -191,77 +180,88 @@ func (c client) CreateExpenseByShare(ctx context.Context, expense Expense, users
 		return nil, err
 	}
 
-	body, err := json.Marshal(expenseByShares)
+	body, err := newExpenseRequestBody(expenseByShares, fields)
 	if err != nil {
 		return nil, err
 	}
 
-	token, err := c.AuthProvider.Auth()
+	var response createExpenseResponse
+	err = c.call(ctx, "CreateExpenseByShare", http.MethodPost, "create_expense", nil, body, &response)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, err
-	}
+	return response.Expenses, nil
+}
 
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Content-Type", "application/json")
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
+// ExpensesOption narrows down the expenses returned by Expenses
+type ExpensesOption func(query url.Values)
 
-	err = c.checkError(res)
-	if err != nil {
-		return nil, err
+// ExpensesGroupID returns only the expenses of the given group
+func ExpensesGroupID(id uint64) ExpensesOption {
+	return func(query url.Values) {
+		query.Set("group_id", strconv.FormatUint(id, 10))
 	}
+}
 
-	var response createExpenseResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
-
-	if err != nil {
-		return nil, err
+// ExpensesFriendID returns only the expenses between the current user and the given friend
+func ExpensesFriendID(id uint64) ExpensesOption {
+	return func(query url.Values) {
+		query.Set("friend_id", strconv.FormatUint(id, 10))
 	}
+}
 
-	return response.Expenses, nil
+// ExpensesDatedAfter returns only the expenses dated after the given time
+func ExpensesDatedAfter(t time.Time) ExpensesOption {
+	return func(query url.Values) {
+		query.Set("dated_after", t.UTC().Format(time.RFC3339))
+	}
 }
 
-func (c client) Expenses(ctx context.Context) ([]ExpenseResponse, error) {
-	url := c.baseURL + "/api/v3.0/get_expenses"
+// ExpensesDatedBefore returns only the expenses dated before the given time
+func ExpensesDatedBefore(t time.Time) ExpensesOption {
+	return func(query url.Values) {
+		query.Set("dated_before", t.UTC().Format(time.RFC3339))
+	}
+}
 
-	token, err := c.AuthProvider.Auth()
-	if err != nil {
-		return nil, err
+// ExpensesUpdatedAfter returns only the expenses updated after the given time
+func ExpensesUpdatedAfter(t time.Time) ExpensesOption {
+	return func(query url.Values) {
+		query.Set("updated_after", t.UTC().Format(time.RFC3339))
 	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
+// ExpensesUpdatedBefore returns only the expenses updated before the given time
+func ExpensesUpdatedBefore(t time.Time) ExpensesOption {
+	return func(query url.Values) {
+		query.Set("updated_before", t.UTC().Format(time.RFC3339))
 	}
+}
 
-	req.Header.Add("Authorization", "Bearer "+token)
+// ExpensesLimit limits the number of the returned expenses
+func ExpensesLimit(limit uint) ExpensesOption {
+	return func(query url.Values) {
+		query.Set("limit", strconv.FormatUint(uint64(limit), 10))
+	}
+}
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
+// ExpensesOffset skips the given number of expenses
+func ExpensesOffset(offset uint) ExpensesOption {
+	return func(query url.Values) {
+		query.Set("offset", strconv.FormatUint(uint64(offset), 10))
 	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
+}
 
-	err = c.checkError(res)
-	if err != nil {
-		return nil, err
+// Expenses returns current user's expenses. Without any option, the default page of the service is returned.
+func (c client) Expenses(ctx context.Context, opts ...ExpensesOption) ([]ExpenseResponse, error) {
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
 	}
 
 	var response expensesResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
-
+	err := c.call(ctx, "Expenses", http.MethodGet, "get_expenses", query, nil, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -274,35 +274,8 @@ type expenseByIDResponse struct {
 }
 
 func (c client) ExpenseByID(ctx context.Context, id uint64) (ExpenseResponse, error) {
-	url := c.baseURL + "/api/v3.0/get_expense/" + strconv.FormatUint(id, 10)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return ExpenseResponse{}, err
-	}
-
-	token, err := c.AuthProvider.Auth()
-	if err != nil {
-		return ExpenseResponse{}, err
-	}
-
-	req.Header.Add("Authorization", "Bearer "+token)
-
-	res, err := c.client.Do(req)
-	if err != nil {
-		return ExpenseResponse{}, err
-	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
-
-	err = c.checkError(res)
-	if err != nil {
-		return ExpenseResponse{}, err
-	}
-
 	var response expenseByIDResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
-
+	err := c.call(ctx, "ExpenseByID", http.MethodGet, "get_expense/"+strconv.FormatUint(id, 10), nil, nil, &response)
 	if err != nil {
 		return ExpenseResponse{}, err
 	}
@@ -344,3 +317,322 @@ func generateUserStruct(userData UserShare, index int) interface{} {
 
 	return value.Addr().Interface()
 }
+
+type mutateExpenseResponse struct {
+	Expenses []ExpenseResponse `json:"expenses"`
+}
+
+// UpdateExpense updates the given fields of an expense. If usersShares is not empty, the shares of the expense are
+// replaced by them, in the same way as CreateExpenseByShare.
+func (c client) UpdateExpense(ctx context.Context, id uint64, usersShares []UserShare, fields ...ExpenseField) ([]ExpenseResponse, error) {
+	shares := map[string]interface{}{}
+	for i, share := range usersShares {
+		shares[fmt.Sprintf("users__%d__user_id", i)] = share.UserID
+		shares[fmt.Sprintf("users__%d__paid_share", i)] = share.PaidShare
+		shares[fmt.Sprintf("users__%d__owed_share", i)] = share.OwedShare
+	}
+
+	body, err := newExpenseRequestBody(shares, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var response mutateExpenseResponse
+	err = c.call(ctx, "UpdateExpense", http.MethodPost, "update_expense/"+strconv.FormatUint(id, 10), nil, body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Expenses, nil
+}
+
+// DeleteExpense deletes an expense by its ID
+func (c client) DeleteExpense(ctx context.Context, id uint64) error {
+	return c.operation(ctx, "DeleteExpense", "delete_expense/"+strconv.FormatUint(id, 10))
+}
+
+// UndeleteExpense restores a deleted expense
+func (c client) UndeleteExpense(ctx context.Context, id uint64) error {
+	return c.operation(ctx, "UndeleteExpense", "undelete_expense/"+strconv.FormatUint(id, 10))
+}
+
+// Payment is an expense which records that a user has paid an amount to another user
+type Payment struct {
+	ExpenseResponse
+	From uint64
+	To   uint64
+}
+
+// RecordPayment records a payment of amount in the given currency from a user to another one, e.g. when settling up.
+// groupID can be zero for the payments outside of groups.
+func (c client) RecordPayment(ctx context.Context, from, to uint64, amount, currency string, groupID uint64) (*Payment, error) {
+	if from == to {
+		return nil, ErrSamePayerAndPayee
+	}
+
+	body := map[string]interface{}{
+		"cost":                 amount,
+		"description":          "Payment",
+		"currency_code":        currency,
+		"group_id":             groupID,
+		"payment":              true,
+		"users__0__user_id":    from,
+		"users__0__paid_share": amount,
+		"users__0__owed_share": "0",
+		"users__1__user_id":    to,
+		"users__1__paid_share": "0",
+		"users__1__owed_share": amount,
+	}
+
+	var response mutateExpenseResponse
+	err := c.call(ctx, "RecordPayment", http.MethodPost, "create_expense", nil, body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response.Expenses) == 0 {
+		return nil, ErrOperationFailed
+	}
+
+	return &Payment{
+		ExpenseResponse: response.Expenses[0],
+		From:            from,
+		To:              to,
+	}, nil
+}
+
+// ParseSentenceOptions are the optional parameters of ParseSentence. GroupID or FriendID, if set, are used as the
+// context of the sentence, e.g. to resolve the names mentioned in it.
+type ParseSentenceOptions struct {
+	Autosave bool
+	GroupID  uint64
+	FriendID uint64
+}
+
+// ParsedSentence is the expense built from a sentence. Valid shows whether the sentence could be parsed completely. The
+// shares of the users are in Expense.Users.
+type ParsedSentence struct {
+	Expense    ExpenseResponse `json:"expense"`
+	Valid      bool            `json:"valid"`
+	Confidence float64         `json:"confidence"`
+	Error      string          `json:"error"`
+}
+
+// ParseSentence builds an expense from a sentence in natural language like "I paid $40 for dinner with Alice". The
+// expense is created only if opts.Autosave is set, otherwise it is just proposed.
+func (c client) ParseSentence(ctx context.Context, input string, opts ParseSentenceOptions) (*ParsedSentence, error) {
+	body := map[string]interface{}{
+		"input":    input,
+		"autosave": opts.Autosave,
+	}
+	if opts.GroupID != 0 {
+		body["group_id"] = opts.GroupID
+	}
+	if opts.FriendID != 0 {
+		body["friend_id"] = opts.FriendID
+	}
+
+	var response ParsedSentence
+	err := c.call(ctx, "ParseSentence", http.MethodPost, "parse_sentence", nil, body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// ExpenseField is a field of an expense which is sent along with the expense requests
+type ExpenseField interface {
+	Key() string
+	Value() interface{}
+}
+
+type expenseField struct {
+	key   string
+	value interface{}
+}
+
+func (e expenseField) Key() string {
+	return e.key
+}
+
+func (e expenseField) Value() interface{} {
+	return e.value
+}
+
+func ExpenseCostField(value string) ExpenseField {
+	return &expenseField{
+		key:   "cost",
+		value: value,
+	}
+}
+
+func ExpenseDescriptionField(value string) ExpenseField {
+	return &expenseField{
+		key:   "description",
+		value: value,
+	}
+}
+
+func ExpenseDetailsField(value string) ExpenseField {
+	return &expenseField{
+		key:   "details",
+		value: value,
+	}
+}
+
+func ExpenseDateField(value string) ExpenseField {
+	return &expenseField{
+		key:   "date",
+		value: value,
+	}
+}
+
+func ExpenseCurrencyCodeField(value string) ExpenseField {
+	return &expenseField{
+		key:   "currency_code",
+		value: value,
+	}
+}
+
+func ExpenseCategoryIDField(value uint32) ExpenseField {
+	return &expenseField{
+		key:   "category_id",
+		value: value,
+	}
+}
+
+func ExpenseGroupIDField(value uint32) ExpenseField {
+	return &expenseField{
+		key:   "group_id",
+		value: value,
+	}
+}
+
+// Receipt is an image attached to an expense
+type Receipt struct {
+	Reader      io.Reader
+	Filename    string
+	ContentType string
+}
+
+// ExpenseReceiptField attaches a receipt image to the expense. The expense request is sent as multipart/form-data when
+// a receipt is attached.
+func ExpenseReceiptField(reader io.Reader, filename, contentType string) ExpenseField {
+	return &expenseField{
+		key: "receipt",
+		value: &Receipt{
+			Reader:      reader,
+			Filename:    filename,
+			ContentType: contentType,
+		},
+	}
+}
+
+// newExpenseRequestBody encodes the payload of an expense request along with the extra fields and returns the encoded
+// body. The body is encoded as JSON, unless a receipt is attached in which case it is encoded as multipart/form-data.
+func newExpenseRequestBody(payload interface{}, fields []ExpenseField) (rawRequestBody, error) {
+	var receipt *Receipt
+	var extraFields []ExpenseField
+	for _, field := range fields {
+		if r, ok := field.Value().(*Receipt); ok {
+			if r == nil || r.Reader == nil {
+				return rawRequestBody{}, ErrReceiptNoReader
+			}
+
+			receipt = r
+			continue
+		}
+		extraFields = append(extraFields, field)
+	}
+
+	if receipt == nil && len(extraFields) == 0 {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return rawRequestBody{}, err
+		}
+
+		return rawRequestBody{body: bytes.NewReader(body), contentType: "application/json"}, nil
+	}
+
+	// Flatten the payload to be able to add the extra fields to it
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return rawRequestBody{}, err
+	}
+
+	values := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(rawPayload))
+	decoder.UseNumber()
+	err = decoder.Decode(&values)
+	if err != nil {
+		return rawRequestBody{}, err
+	}
+
+	for _, field := range extraFields {
+		values[field.Key()] = field.Value()
+	}
+
+	if receipt == nil {
+		body, err := json.Marshal(values)
+		if err != nil {
+			return rawRequestBody{}, err
+		}
+
+		return rawRequestBody{body: bytes.NewReader(body), contentType: "application/json"}, nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, key := range keys {
+		err = writer.WriteField(key, formValue(values[key]))
+		if err != nil {
+			return rawRequestBody{}, err
+		}
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="receipt"; filename=%q`, receipt.Filename))
+	if receipt.ContentType != "" {
+		header.Set("Content-Type", receipt.ContentType)
+	} else {
+		header.Set("Content-Type", "application/octet-stream")
+	}
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return rawRequestBody{}, err
+	}
+
+	_, err = io.Copy(part, receipt.Reader)
+	if err != nil {
+		return rawRequestBody{}, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return rawRequestBody{}, err
+	}
+
+	return rawRequestBody{body: &body, contentType: writer.FormDataContentType()}, nil
+}
+
+// formValue converts a field value to its representation in a form
+func formValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
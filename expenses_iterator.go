@@ -0,0 +1,126 @@
+package splitwise
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// DefaultExpensesPageSize is the number of expenses fetched in each page by ExpenseIterator, unless it is set by
+// ExpensesLimit
+const DefaultExpensesPageSize = 100
+
+// ExpenseIterator walks through all the expenses matching the given options by fetching them page by page. It is
+// not safe for concurrent use.
+//
+//	it := splitwise.NewExpenseIterator(ctx, client, splitwise.ExpensesGroupID(groupID))
+//	for it.Next() {
+//		expense := it.Expense()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ExpenseIterator struct {
+	ctx      context.Context
+	expenses Expenses
+	opts     []ExpensesOption
+
+	pageSize uint
+	offset   uint
+	lastPage bool
+
+	page    []ExpenseResponse
+	index   int
+	current ExpenseResponse
+	err     error
+}
+
+// NewExpenseIterator returns an iterator over the expenses matching the given options. ExpensesLimit sets the page
+// size and ExpensesOffset sets the number of expenses to skip before the first page.
+func NewExpenseIterator(ctx context.Context, expenses Expenses, opts ...ExpensesOption) *ExpenseIterator {
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+
+	pageSize := uint(DefaultExpensesPageSize)
+	if limit, err := strconv.ParseUint(query.Get("limit"), 10, 64); err == nil && limit != 0 {
+		pageSize = uint(limit)
+	}
+
+	var offset uint
+	if value, err := strconv.ParseUint(query.Get("offset"), 10, 64); err == nil {
+		offset = uint(value)
+	}
+
+	return &ExpenseIterator{
+		ctx:      ctx,
+		expenses: expenses,
+		opts:     opts,
+		pageSize: pageSize,
+		offset:   offset,
+	}
+}
+
+// Next advances the iterator to the next expense, fetching the next page if needed. It returns false when there are
+// no more expenses or an error has happened.
+func (it *ExpenseIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if it.index >= len(it.page) {
+		if it.lastPage {
+			return false
+		}
+
+		opts := append(it.opts[:len(it.opts):len(it.opts)], ExpensesLimit(it.pageSize), ExpensesOffset(it.offset))
+		page, err := it.expenses.Expenses(it.ctx, opts...)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = page
+		it.index = 0
+		it.offset += uint(len(page))
+		it.lastPage = uint(len(page)) < it.pageSize
+
+		if len(page) == 0 {
+			return false
+		}
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+
+	return true
+}
+
+// Expense returns the current expense of the iterator
+func (it *ExpenseIterator) Expense() ExpenseResponse {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *ExpenseIterator) Err() error {
+	return it.err
+}
+
+// ForEachExpense calls fn for all the expenses matching the given options. It stops on the first error returned by fn.
+func ForEachExpense(ctx context.Context, expenses Expenses, fn func(expense ExpenseResponse) error, opts ...ExpensesOption) error {
+	it := NewExpenseIterator(ctx, expenses, opts...)
+	for it.Next() {
+		if err := fn(it.Expense()); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+}
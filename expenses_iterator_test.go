@@ -0,0 +1,117 @@
+package splitwise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestExpenseIterator(t *testing.T) {
+	const total = 5
+
+	// Start a local HTTP server
+	newServer := func(t *testing.T) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != "/api/v3.0/get_expenses" {
+				t.Error("invalid URL request")
+			}
+
+			if req.URL.Query().Get("group_id") != "391" {
+				t.Error("missing group filter")
+			}
+
+			limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+			offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+
+			var expenses []string
+			for id := offset + 1; id <= total && id <= offset+limit; id++ {
+				expenses = append(expenses, fmt.Sprintf(`{"id": %d}`, id))
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"expenses": [` + strings.Join(expenses, ",") + `]}`))
+		}))
+	}
+
+	t.Run("all pages", func(t *testing.T) {
+		server := newServer(t)
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		var ids []uint64
+		it := NewExpenseIterator(context.Background(), c, ExpensesGroupID(391), ExpensesLimit(2))
+		for it.Next() {
+			ids = append(ids, it.Expense().ID)
+		}
+
+		if it.Err() != nil {
+			t.Fatal(it.Err())
+		}
+
+		if fmt.Sprint(ids) != "[1 2 3 4 5]" {
+			t.Errorf("unexpected expenses: %v", ids)
+		}
+	})
+
+	t.Run("for each with offset", func(t *testing.T) {
+		server := newServer(t)
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		var ids []uint64
+		err := ForEachExpense(context.Background(), c, func(expense ExpenseResponse) error {
+			ids = append(ids, expense.ID)
+			return nil
+		}, ExpensesGroupID(391), ExpensesOffset(3))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if fmt.Sprint(ids) != "[4 5]" {
+			t.Errorf("unexpected expenses: %v", ids)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		server := newServer(t)
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var count int
+		err := ForEachExpense(ctx, c, func(expense ExpenseResponse) error {
+			count++
+			cancel()
+			return nil
+		}, ExpensesGroupID(391))
+
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+
+		if count != 1 {
+			t.Errorf("expected the iteration to stop after the first expense, got %d", count)
+		}
+	})
+}
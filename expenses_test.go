@@ -3,10 +3,13 @@ package splitwise
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestClient_CreateExpenseSplitEqually(t *testing.T) {
@@ -367,6 +370,32 @@ func TestClient_CreateExpenseByShare(t *testing.T) {
 			t.Fatal(err)
 		}
 	})
+
+	t.Run("errors", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"expenses": [], "errors": {"base": ["The total of everyone's paid shares must equal the cost"]}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		_, err := c.CreateExpenseByShare(context.Background(), Expense{Cost: "25"}, []UserShare{{UserID: 1, PaidShare: "20", OwedShare: "25"}})
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected a validation error, got %v", err)
+		}
+
+		if validationErr.Errors.String() != "The total of everyone's paid shares must equal the cost" {
+			t.Errorf("unexpected errors %v", validationErr.Errors)
+		}
+	})
 }
 
 func TestClient_GetExpenseCurrentUser(t *testing.T) {
@@ -611,6 +640,23 @@ func TestClient_GetExpenseByID(t *testing.T) {
 						"created_at": "2019-08-24T14:15:22Z",
 						"deleted_at": "2019-08-24T14:15:22Z",
 						"user": null
+					  },
+					  {
+						"id": 79800951,
+						"content": "Thanks!",
+						"comment_type": "User",
+						"relation_type": "ExpenseComment",
+						"relation_id": 855870953,
+						"created_at": "2019-08-24T14:16:22Z",
+						"deleted_at": null,
+						"user": {
+						  "id": 491923,
+						  "first_name": "Jane",
+						  "last_name": "Doe",
+						  "picture": {
+							"medium": "image_url"
+						  }
+						}
 					  }
 					]
 				  }
@@ -624,10 +670,431 @@ func TestClient_GetExpenseByID(t *testing.T) {
 			client:       http.DefaultClient,
 		}
 
-		_, err := c.ExpenseByID(context.Background(), 10)
+		expense, err := c.ExpenseByID(context.Background(), 10)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(expense.Comments) != 2 || expense.Comments[0].User != nil || expense.Comments[1].User == nil ||
+			expense.Comments[1].User.ID != 491923 {
+			t.Errorf("unexpected comments %+v", expense.Comments)
+		}
+	})
+}
+
+func TestClient_UpdateExpense(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/update_expense/51023" {
+				t.Error("invalid URL request")
+			}
+
+			reqBody, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fail()
+			}
+
+			expectedReqBody := `{"category_id":18,"cost":"30","description":"Brunch","users__0__owed_share":"15","users__0__paid_share":"30","users__0__user_id":54123,"users__1__owed_share":"15","users__1__paid_share":"0","users__1__user_id":34262}`
+			if string(reqBody) != expectedReqBody {
+				t.Log(string(reqBody))
+				t.FailNow()
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"expenses": [
+					{
+						"id": 51023,
+						"cost": "30.0",
+						"description": "Brunch",
+						"currency_code": "USD",
+						"category_id": 18,
+						"group_id": 391,
+						"users": [
+							{"user_id": 54123, "paid_share": "30.0", "owed_share": "15.0", "net_balance": "15.0"},
+							{"user_id": 34262, "paid_share": "0.0", "owed_share": "15.0", "net_balance": "-15.0"}
+						]
+					}
+				],
+				"errors": {}
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		expenses, err := c.UpdateExpense(context.Background(), 51023,
+			[]UserShare{
+				{UserID: 54123, PaidShare: "30", OwedShare: "15"},
+				{UserID: 34262, PaidShare: "0", OwedShare: "15"},
+			},
+			ExpenseCostField("30"),
+			ExpenseDescriptionField("Brunch"),
+			ExpenseCategoryIDField(18),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(expenses) != 1 || expenses[0].ID != 51023 || expenses[0].Cost != "30.0" {
+			t.Error("invalid updated expense")
+		}
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"expenses": [], "errors": {"cost": ["must be a number"]}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		_, err := c.UpdateExpense(context.Background(), 51023, nil, ExpenseCostField("thirty"))
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+	})
+}
+
+func TestClient_DeleteExpense(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/delete_expense/51023" {
+				t.Error("invalid URL request")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"success": true, "errors": {}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		err := c.DeleteExpense(context.Background(), 51023)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestClient_UndeleteExpense(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/undelete_expense/51023" {
+				t.Error("invalid URL request")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"success": true, "errors": {}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		err := c.UndeleteExpense(context.Background(), 51023)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestClient_ExpensesWithOptions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			expectedURL := "/api/v3.0/get_expenses?dated_after=2021-01-01T00%3A00%3A00Z&dated_before=2022-01-01T00%3A00%3A00Z&group_id=391&limit=50&offset=100"
+			if req.URL.String() != expectedURL {
+				t.Errorf("invalid URL request: %s", req.URL.String())
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"expenses": [{"id": 51023, "cost": "25.0", "group_id": 391}]}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		expenses, err := c.Expenses(context.Background(),
+			ExpensesGroupID(391),
+			ExpensesDatedAfter(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)),
+			ExpensesDatedBefore(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+			ExpensesLimit(50),
+			ExpensesOffset(100),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(expenses) != 1 {
+			t.Error("invalid expenses")
+		}
+	})
+}
+
+func TestClient_ParseSentence(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/parse_sentence" {
+				t.Error("invalid URL request")
+			}
+
+			reqBody, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fail()
+			}
+
+			expectedReqBody := `{"autosave":false,"group_id":391,"input":"I paid $40 for dinner with Alice"}`
+			if string(reqBody) != expectedReqBody {
+				t.FailNow()
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"expense": {
+					"cost": "40.0",
+					"description": "dinner",
+					"currency_code": "USD",
+					"group_id": 391,
+					"users": [
+						{"user_id": 54123, "paid_share": "40.0", "owed_share": "20.0", "net_balance": "20.0"},
+						{"user_id": 34262, "paid_share": "0.0", "owed_share": "20.0", "net_balance": "-20.0"}
+					]
+				},
+				"valid": true,
+				"confidence": 0.9,
+				"error": ""
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		parsed, err := c.ParseSentence(context.Background(), "I paid $40 for dinner with Alice", ParseSentenceOptions{GroupID: 391})
+		if err != nil {
+			t.Fatal(err)
+		}
 
+		if !parsed.Valid || parsed.Expense.Cost != "40.0" || len(parsed.Expense.Users) != 2 {
+			t.Error("invalid parsed sentence")
+		}
+	})
+}
+
+func TestClient_CreateExpenseWithReceipt(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/create_expense" {
+				t.Error("invalid URL request")
+			}
+
+			err := req.ParseMultipartForm(1 << 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if req.FormValue("cost") != "25" || req.FormValue("users__1__user_id") != "34262" ||
+				req.FormValue("users__1__owed_share") != "10" || req.FormValue("category_id") != "15" {
+				t.Errorf("invalid form values: %v", req.MultipartForm.Value)
+			}
+
+			file, header, err := req.FormFile("receipt")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			content, _ := io.ReadAll(file)
+			if string(content) != "image-content" || header.Filename != "receipt.png" ||
+				header.Header.Get("Content-Type") != "image/png" {
+				t.Error("invalid receipt")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"expenses": [{"cost": "25.0", "description": "Grocery run"}], "errors": {}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		_, err := c.CreateExpenseByShare(context.Background(),
+			Expense{
+				Cost:         "25",
+				Description:  "Grocery run",
+				CurrencyCode: "USD",
+				CategoryId:   15,
+			},
+			[]UserShare{
+				{UserID: 54123, PaidShare: "25", OwedShare: "15"},
+				{UserID: 34262, PaidShare: "0", OwedShare: "10"},
+			},
+			ExpenseReceiptField(strings.NewReader("image-content"), "receipt.png", "image/png"),
+		)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
+
+	t.Run("no reader", func(t *testing.T) {
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      "http://127.0.0.1:0",
+			client:       http.DefaultClient,
+		}
+
+		_, err := c.CreateExpenseByShare(context.Background(),
+			Expense{Cost: "25", Description: "Grocery run"},
+			[]UserShare{{UserID: 54123, PaidShare: "25", OwedShare: "25"}},
+			ExpenseReceiptField(nil, "receipt.png", "image/png"),
+		)
+		if !errors.Is(err, ErrReceiptNoReader) {
+			t.Fatalf("expected ErrReceiptNoReader, got %v", err)
+		}
+	})
+}
+
+func TestClient_UpdateExpenseWithReceipt(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/update_expense/51023" {
+				t.Error("invalid URL request")
+			}
+
+			err := req.ParseMultipartForm(1 << 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if req.FormValue("description") != "Brunch" {
+				t.Errorf("invalid form values: %v", req.MultipartForm.Value)
+			}
+
+			if _, _, err := req.FormFile("receipt"); err != nil {
+				t.Fatal(err)
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"expenses": [{"id": 51023, "description": "Brunch"}], "errors": {}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		_, err := c.UpdateExpense(context.Background(), 51023, nil,
+			ExpenseDescriptionField("Brunch"),
+			ExpenseReceiptField(strings.NewReader("image-content"), "receipt.jpg", "image/jpeg"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestClient_RecordPayment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/create_expense" {
+				t.Error("invalid URL request")
+			}
+
+			reqBody, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fail()
+			}
+
+			expectedReqBody := `{"cost":"23.45","currency_code":"USD","description":"Payment","group_id":391,"payment":true,"users__0__owed_share":"0","users__0__paid_share":"23.45","users__0__user_id":54123,"users__1__owed_share":"23.45","users__1__paid_share":"0","users__1__user_id":34262}`
+			if string(reqBody) != expectedReqBody {
+				t.FailNow()
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"expenses": [
+					{
+						"id": 51024,
+						"cost": "23.45",
+						"description": "Payment",
+						"currency_code": "USD",
+						"group_id": 391,
+						"payment": true,
+						"users": [
+							{"user_id": 54123, "paid_share": "23.45", "owed_share": "0.0", "net_balance": "23.45"},
+							{"user_id": 34262, "paid_share": "0.0", "owed_share": "23.45", "net_balance": "-23.45"}
+						]
+					}
+				],
+				"errors": {}
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		payment, err := c.RecordPayment(context.Background(), 54123, 34262, "23.45", "USD", 391)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if payment.ID != 51024 || !payment.Payment || payment.From != 54123 || payment.To != 34262 {
+			t.Error("invalid payment")
+		}
+	})
+
+	t.Run("same payer and payee", func(t *testing.T) {
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			client:       http.DefaultClient,
+		}
+
+		_, err := c.RecordPayment(context.Background(), 54123, 54123, "23.45", "USD", 0)
+		if !errors.Is(err, ErrSamePayerAndPayee) {
+			t.Fatalf("expected ErrSamePayerAndPayee, got %v", err)
+		}
+	})
 }
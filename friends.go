@@ -2,9 +2,11 @@ package splitwise
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,10 +15,38 @@ type Friends interface {
 	// Friends returns current user's friends
 	Friends(ctx context.Context) ([]Friend, error)
 
+	// FriendByID returns information about a friend of the current user by their ID
+	FriendByID(ctx context.Context, id uint64) (*Friend, error)
+
+	// CreateFriend adds a friend by their email and returns the result. The first and last name are used in case the
+	// invitee is not a Splitwise user yet.
+	CreateFriend(ctx context.Context, invitee FriendInvitee) (*Friend, error)
+
+	// CreateFriends adds multiple friends at once. The errors related to each invitee are returned separately so that
+	// a single invalid invitee does not hide the created friends.
+	CreateFriends(ctx context.Context, invitees []FriendInvitee) ([]Friend, []FriendInviteError, error)
+
 	// DeleteFriend Given a friend ID, break off the friendship between the current user and the specified user.
 	DeleteFriend(ctx context.Context, id uint64) (bool, error)
 }
 
+// FriendInvitee identifies a user to be added as a friend. FirstName and LastName are optional.
+type FriendInvitee struct {
+	Email     string
+	FirstName string
+	LastName  string
+}
+
+// FriendInviteError holds the errors reported for one of the invitees of CreateFriends
+type FriendInviteError struct {
+	Invitee  FriendInvitee
+	Messages []string
+}
+
+func (e FriendInviteError) Error() string {
+	return fmt.Sprintf("can't add %s as a friend: %s", e.Invitee.Email, strings.Join(e.Messages, ", "))
+}
+
 type Friend struct {
 	ID                 int    `json:"id"`
 	FirstName          string `json:"first_name"`
@@ -47,73 +77,130 @@ type friendsResponse struct {
 }
 
 func (c client) Friends(ctx context.Context) ([]Friend, error) {
-	url := c.baseURL + "/api/v3.0/get_friends"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	var response friendsResponse
+	err := c.call(ctx, "Friends", http.MethodGet, "get_friends", nil, nil, &response)
 	if err != nil {
 		return nil, err
 	}
 
-	token, err := c.AuthProvider.Auth()
-	if err != nil {
-		return nil, err
-	}
+	return response.Friends, nil
+}
 
-	req.Header.Add("Authorization", "Bearer "+token)
-	res, err := c.client.Do(req)
+type friendResponse struct {
+	Friend Friend `json:"friend"`
+}
+
+// FriendByID returns information about a friend of the current user by their ID
+func (c client) FriendByID(ctx context.Context, id uint64) (*Friend, error) {
+	var response friendResponse
+	err := c.call(ctx, "FriendByID", http.MethodGet, "get_friend/"+strconv.FormatUint(id, 10), nil, nil, &response)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
 
-	err = c.checkError(res)
-	if err != nil {
-		return nil, err
+	return &response.Friend, nil
+}
+
+// CreateFriend adds a friend by their email and returns the result. The first and last name are used in case the
+// invitee is not a Splitwise user yet.
+func (c client) CreateFriend(ctx context.Context, invitee FriendInvitee) (*Friend, error) {
+	body := map[string]interface{}{
+		"user_email": invitee.Email,
+	}
+	if invitee.FirstName != "" {
+		body["user_first_name"] = invitee.FirstName
+	}
+	if invitee.LastName != "" {
+		body["user_last_name"] = invitee.LastName
 	}
 
-	var response friendsResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
+	var response friendResponse
+	err := c.call(ctx, "CreateFriend", http.MethodPost, "create_friend", nil, body, &response)
 	if err != nil {
 		return nil, err
 	}
 
-	return response.Friends, nil
+	return &response.Friend, nil
 }
 
-type deleteFriendResponse struct {
-	Success bool          `json:"success"`
-	Errors  []interface{} `json:"errors"`
+type createFriendsResponse struct {
+	Users  []Friend       `json:"users"`
+	Errors ResponseErrors `json:"errors"`
 }
 
-func (c client) DeleteFriend(ctx context.Context, id uint64) (bool, error) {
-	url := c.baseURL + "/api/v3.0/delete_friend/" + strconv.FormatUint(id, 10)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
-	if err != nil {
-		return false, err
+// CreateFriends adds multiple friends at once. The errors related to each invitee are returned separately so that a
+// single invalid invitee does not hide the created friends. The errors which can't be related to an invitee are
+// returned as a *ValidationError.
+func (c client) CreateFriends(ctx context.Context, invitees []FriendInvitee) ([]Friend, []FriendInviteError, error) {
+	body := map[string]interface{}{}
+	for i, invitee := range invitees {
+		prefix := fmt.Sprintf("friends__%d__", i)
+		body[prefix+"email"] = invitee.Email
+		if invitee.FirstName != "" {
+			body[prefix+"first_name"] = invitee.FirstName
+		}
+		if invitee.LastName != "" {
+			body[prefix+"last_name"] = invitee.LastName
+		}
 	}
 
-	token, err := c.AuthProvider.Auth()
-	if err != nil {
-		return false, err
+	// The reported errors are split between the invitees below, instead of being returned as a whole
+	var response createFriendsResponse
+	err := c.call(ctx, "CreateFriends", http.MethodPost, "create_friends", nil, body, &response)
+	var validationErr *ValidationError
+	if err != nil && !errors.As(err, &validationErr) {
+		return nil, nil, err
 	}
 
-	req.Header.Add("Authorization", "Bearer "+token)
-	res, err := c.client.Do(req)
-	if err != nil {
-		return false, err
+	inviteErrors, remaining := splitInviteErrors(invitees, response.Errors)
+
+	return response.Users, inviteErrors, checkOperationResult(true, remaining)
+}
+
+// splitInviteErrors relates the reported errors to the invitees, either by the "friends__<index>__" prefix of their
+// keys or by the invitee's email mentioned in the message. The errors which can't be related are returned as remaining.
+func splitInviteErrors(invitees []FriendInvitee, responseErrors ResponseErrors) ([]FriendInviteError, ResponseErrors) {
+	messages := make([][]string, len(invitees))
+	remaining := ResponseErrors{}
+
+	for _, key := range responseErrors.sortedKeys() {
+		keyMessages := responseErrors[key]
+
+		var index int
+		var field string
+		if _, err := fmt.Sscanf(strings.Replace(key, "__", " ", -1), "friends %d %s", &index, &field); err == nil &&
+			index >= 0 && index < len(invitees) {
+			for _, message := range keyMessages {
+				messages[index] = append(messages[index], field+" "+message)
+			}
+			continue
+		}
+
+	nextMessage:
+		for _, message := range keyMessages {
+			for i, invitee := range invitees {
+				if invitee.Email != "" && strings.Contains(message, invitee.Email) {
+					messages[i] = append(messages[i], message)
+					continue nextMessage
+				}
+			}
+			remaining[key] = append(remaining[key], message)
+		}
 	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
 
-	err = c.checkError(res)
-	if err != nil {
-		return false, err
+	var inviteErrors []FriendInviteError
+	for i, inviteeMessages := range messages {
+		if len(inviteeMessages) != 0 {
+			inviteErrors = append(inviteErrors, FriendInviteError{Invitee: invitees[i], Messages: inviteeMessages})
+		}
 	}
 
-	var response deleteFriendResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
+	return inviteErrors, remaining
+}
+
+func (c client) DeleteFriend(ctx context.Context, id uint64) (bool, error) {
+	var response operationResponse
+	err := c.call(ctx, "DeleteFriend", http.MethodPost, "delete_friend/"+strconv.FormatUint(id, 10), nil, nil, &response)
 	if err != nil {
 		return false, err
 	}
@@ -2,6 +2,8 @@ package splitwise
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -64,3 +66,217 @@ func TestClient_Friends(t *testing.T) {
 		}
 	})
 }
+
+func TestClient_FriendByID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/get_friend/1313" {
+				t.Error("invalid URL request")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"friend": {
+					"id": 1313,
+					"first_name": "John",
+					"last_name": "Petrucci",
+					"email": "john@gmail.com",
+					"registration_status": "confirmed",
+					"groups": [
+						{
+							"group_id": 571,
+							"balance": [{"currency_code": "USD", "amount": "414.5"}]
+						}
+					],
+					"balance": [{"currency_code": "USD", "amount": "414.5"}],
+					"updated_at": "2019-08-24T14:15:22Z"
+				}
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		friend, err := c.FriendByID(context.Background(), 1313)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if friend.ID != 1313 {
+			t.Error("invalid friend")
+		}
+	})
+}
+
+func TestClient_CreateFriend(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/create_friend" {
+				t.Error("invalid URL request")
+			}
+
+			reqBody, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fail()
+			}
+
+			expectedReqBody := `{"user_email":"ada@example.com","user_first_name":"Ada"}`
+			if string(reqBody) != expectedReqBody {
+				t.FailNow()
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"friend": {
+					"id": 1515,
+					"first_name": "Ada",
+					"last_name": null,
+					"email": "ada@example.com",
+					"registration_status": "invited",
+					"groups": [],
+					"balance": []
+				}
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		friend, err := c.CreateFriend(context.Background(), FriendInvitee{Email: "ada@example.com", FirstName: "Ada"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if friend.ID != 1515 {
+			t.Error("invalid friend")
+		}
+	})
+}
+
+func TestClient_CreateFriends(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/create_friends" {
+				t.Error("invalid URL request")
+			}
+
+			reqBody, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fail()
+			}
+
+			expectedReqBody := `{"friends__0__email":"ada@example.com","friends__0__first_name":"Ada","friends__0__last_name":"Lovelace","friends__1__email":"alan@example"}`
+			if string(reqBody) != expectedReqBody {
+				t.FailNow()
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"users": [
+					{
+						"id": 1515,
+						"first_name": "Ada",
+						"last_name": "Lovelace",
+						"email": "ada@example.com",
+						"registration_status": "invited",
+						"groups": [],
+						"balance": []
+					}
+				],
+				"errors": {
+					"friends__1__email": ["is invalid"]
+				}
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		friends, inviteErrors, err := c.CreateFriends(context.Background(), []FriendInvitee{
+			{Email: "ada@example.com", FirstName: "Ada", LastName: "Lovelace"},
+			{Email: "alan@example"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(friends) != 1 || friends[0].ID != 1515 {
+			t.Error("invalid friends")
+		}
+
+		if len(inviteErrors) != 1 || inviteErrors[0].Invitee.Email != "alan@example" {
+			t.Fatalf("invalid invite errors: %v", inviteErrors)
+		}
+
+		if inviteErrors[0].Error() != "can't add alan@example as a friend: email is invalid" {
+			t.Errorf("unexpected error message: %s", inviteErrors[0].Error())
+		}
+	})
+}
+
+func TestClient_DeleteFriend(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/delete_friend/1313" {
+				t.Error("invalid URL request")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"success": true, "errors": []}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		success, err := c.DeleteFriend(context.Background(), 1313)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !success {
+			t.Error("expected the friend to be deleted")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"success": false, "errors": {"base": ["You have a non-zero balance with this friend"]}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		success, err := c.DeleteFriend(context.Background(), 1313)
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || success {
+			t.Fatalf("expected a validation error, got %t, %v", success, err)
+		}
+	})
+}
@@ -2,7 +2,8 @@ package splitwise
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -18,11 +19,40 @@ type Groups interface {
 
 	// GroupByID returns information about a group by its ID
 	GroupByID(ctx context.Context, id uint64) (*Group, error)
+
+	// CreateGroup creates a new group and adds the current user to it. The initial members can be given by their user
+	// IDs, or by their email, first name and last name.
+	CreateGroup(ctx context.Context, group CreateGroupDTO) (*Group, error)
+
+	// DeleteGroup deletes an existing group and destroys all associated records (expenses, etc.)
+	DeleteGroup(ctx context.Context, id uint64) error
+
+	// UndeleteGroup restores a deleted group
+	UndeleteGroup(ctx context.Context, id uint64) error
+
+	// AddUserToGroup adds a user to a group. The user is identified by their user ID, or by their email, first name
+	// and last name in which case they will be invited to Splitwise if needed.
+	AddUserToGroup(ctx context.Context, groupID uint64, user GroupUser) (*User, error)
+
+	// RemoveUserFromGroup removes a user from a group. ErrNonZeroBalance is returned if the user has a non-zero balance
+	// in the group.
+	RemoveUserFromGroup(ctx context.Context, groupID uint64, userID uint64) error
 }
 
+// GroupType is the kind of group that is shown in the Splitwise apps
+type GroupType string
+
+const (
+	GroupTypeApartment GroupType = "apartment"
+	GroupTypeHouse     GroupType = "house"
+	GroupTypeTrip      GroupType = "trip"
+	GroupTypeOther     GroupType = "other"
+)
+
 type Group struct {
 	ID                uint64        `json:"id"`
 	Name              string        `json:"name"`
+	GroupType         GroupType     `json:"group_type"`
 	CreatedAt         time.Time     `json:"created_at"`
 	UpdatedAt         time.Time     `json:"updated_at"`
 	Members           []GroupMember `json:"members"`
@@ -78,75 +108,142 @@ type groupsResponse struct {
 }
 
 func (c client) Groups(ctx context.Context) ([]Group, error) {
-	url := c.baseURL + "/api/v3.0/get_groups"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	var response groupsResponse
+	err := c.call(ctx, "Groups", http.MethodGet, "get_groups", nil, nil, &response)
 	if err != nil {
 		return nil, err
 	}
 
-	token, err := c.AuthProvider.Auth()
-	if err != nil {
-		return nil, err
-	}
+	return response.Groups, nil
+}
 
-	req.Header.Add("Authorization", "Bearer "+token)
-	res, err := c.client.Do(req)
+type groupByIDResponse struct {
+	Group Group `json:"group"`
+}
+
+func (c client) GroupByID(ctx context.Context, id uint64) (*Group, error) {
+	var response groupByIDResponse
+	err := c.call(ctx, "GroupByID", http.MethodGet, "get_group/"+strconv.FormatUint(id, 10), nil, nil, &response)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
 
-	err = c.checkError(res)
-	if err != nil {
-		return nil, err
+	return &response.Group, nil
+}
+
+// GroupUser identifies a user for the group operations. An existing user is identified by UserID, otherwise Email,
+// FirstName and LastName are used to invite them.
+type GroupUser struct {
+	UserID    uint64
+	Email     string
+	FirstName string
+	LastName  string
+}
+
+// fields returns the request body fields that identify the user, each key being prefixed by the given prefix
+func (u GroupUser) fields(prefix string) map[string]interface{} {
+	if u.UserID != 0 {
+		return map[string]interface{}{
+			prefix + "user_id": u.UserID,
+		}
 	}
 
-	var response groupsResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
-	if err != nil {
-		return nil, err
+	return map[string]interface{}{
+		prefix + "email":      u.Email,
+		prefix + "first_name": u.FirstName,
+		prefix + "last_name":  u.LastName,
 	}
+}
 
-	return response.Groups, nil
+type CreateGroupDTO struct {
+	Name              string
+	GroupType         GroupType
+	SimplifyByDefault bool
+	Users             []GroupUser
 }
 
-type groupByIDResponse struct {
+type createGroupResponse struct {
 	Group Group `json:"group"`
 }
 
-func (c client) GroupByID(ctx context.Context, id uint64) (*Group, error) {
-	url := c.baseURL + "/api/v3.0/get_group/" + strconv.FormatUint(id, 10)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
+// CreateGroup creates a new group and adds the current user to it. The initial members can be given by their user IDs,
+// or by their email, first name and last name.
+func (c client) CreateGroup(ctx context.Context, group CreateGroupDTO) (*Group, error) {
+	body := map[string]interface{}{
+		"name":                group.Name,
+		"simplify_by_default": group.SimplifyByDefault,
+	}
+	if group.GroupType != "" {
+		body["group_type"] = group.GroupType
+	}
+	for i, user := range group.Users {
+		for key, value := range user.fields(fmt.Sprintf("users__%d__", i)) {
+			body[key] = value
+		}
 	}
 
-	token, err := c.AuthProvider.Auth()
+	var response createGroupResponse
+	err := c.call(ctx, "CreateGroup", http.MethodPost, "create_group", nil, body, &response)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Authorization", "Bearer "+token)
-	res, err := c.client.Do(req)
+	return &response.Group, nil
+}
+
+// DeleteGroup deletes an existing group and destroys all associated records (expenses, etc.)
+func (c client) DeleteGroup(ctx context.Context, id uint64) error {
+	return c.operation(ctx, "DeleteGroup", "delete_group/"+strconv.FormatUint(id, 10))
+}
+
+// UndeleteGroup restores a deleted group
+func (c client) UndeleteGroup(ctx context.Context, id uint64) error {
+	return c.operation(ctx, "UndeleteGroup", "undelete_group/"+strconv.FormatUint(id, 10))
+}
+
+type addUserToGroupResponse struct {
+	Success bool `json:"success"`
+	User    User `json:"user"`
+}
+
+// AddUserToGroup adds a user to a group. The user is identified by their user ID, or by their email, first name and
+// last name in which case they will be invited to Splitwise if needed.
+func (c client) AddUserToGroup(ctx context.Context, groupID uint64, user GroupUser) (*User, error) {
+	body := user.fields("")
+	body["group_id"] = groupID
+
+	var response addUserToGroupResponse
+	err := c.call(ctx, "AddUserToGroup", http.MethodPost, "add_user_to_group", nil, body, &response)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
 
-	err = c.checkError(res)
+	err = checkOperationResult(response.Success, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var response groupByIDResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
-	if err != nil {
-		return nil, err
+	return &response.User, nil
+}
+
+// RemoveUserFromGroup removes a user from a group. ErrNonZeroBalance is returned if the user has a non-zero balance in
+// the group.
+func (c client) RemoveUserFromGroup(ctx context.Context, groupID uint64, userID uint64) error {
+	body := map[string]interface{}{
+		"group_id": groupID,
+		"user_id":  userID,
 	}
 
-	return &response.Group, nil
+	var response operationResponse
+	err := c.call(ctx, "RemoveUserFromGroup", http.MethodPost, "remove_user_from_group", nil, body, &response)
+	if err == nil {
+		err = checkOperationResult(response.Success, nil)
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) && validationErr.contains("balance") {
+		validationErr.cause = ErrNonZeroBalance
+	}
+
+	return err
 }
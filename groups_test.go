@@ -2,6 +2,8 @@ package splitwise
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -208,3 +210,277 @@ func TestClient_GroupByID(t *testing.T) {
 		}
 	})
 }
+
+func TestClient_CreateGroup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/create_group" {
+				t.Error("invalid URL request")
+			}
+
+			reqBody, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fail()
+			}
+
+			expectedReqBody := `{"group_type":"trip","name":"Japan","simplify_by_default":true,"users__0__user_id":1313,"users__1__email":"alan@example.org","users__1__first_name":"Alan","users__1__last_name":"Turing"}`
+			if string(reqBody) != expectedReqBody {
+				t.FailNow()
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"group": {
+					"id": 321,
+					"name": "Japan",
+					"group_type": "trip",
+					"created_at": "2021-11-25T18:31:14Z",
+					"updated_at": "2021-11-25T18:31:14Z",
+					"simplify_by_default": true,
+					"members": [],
+					"original_debts": [],
+					"simplified_debts": []
+				},
+				"errors": {}
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		group, err := c.CreateGroup(context.Background(), CreateGroupDTO{
+			Name:              "Japan",
+			GroupType:         GroupTypeTrip,
+			SimplifyByDefault: true,
+			Users: []GroupUser{
+				{UserID: 1313},
+				{Email: "alan@example.org", FirstName: "Alan", LastName: "Turing"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if group.ID != 321 || group.GroupType != GroupTypeTrip {
+			t.Error("invalid group")
+		}
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"group": {}, "errors": {"name": ["can't be blank"]}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		_, err := c.CreateGroup(context.Background(), CreateGroupDTO{})
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+
+		if validationErr.Errors["name"][0] != "can't be blank" {
+			t.Error("invalid validation error")
+		}
+	})
+}
+
+func TestClient_DeleteGroup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/delete_group/321" {
+				t.Error("invalid URL request")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"success": true}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		err := c.DeleteGroup(context.Background(), 321)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("unsuccessful", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"success": false, "errors": []}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		err := c.DeleteGroup(context.Background(), 321)
+		if !errors.Is(err, ErrOperationFailed) {
+			t.Fatalf("expected ErrOperationFailed, got %v", err)
+		}
+	})
+}
+
+func TestClient_UndeleteGroup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/undelete_group/321" {
+				t.Error("invalid URL request")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"success": true, "errors": []}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		err := c.UndeleteGroup(context.Background(), 321)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestClient_AddUserToGroup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/add_user_to_group" {
+				t.Error("invalid URL request")
+			}
+
+			reqBody, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fail()
+			}
+
+			expectedReqBody := `{"email":"alan@example.org","first_name":"Alan","group_id":321,"last_name":"Turing"}`
+			if string(reqBody) != expectedReqBody {
+				t.FailNow()
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"success": true,
+				"user": {
+					"id": 1414,
+					"first_name": "Alan",
+					"last_name": "Turing",
+					"email": "alan@example.org",
+					"registration_status": "invited"
+				},
+				"errors": {}
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		user, err := c.AddUserToGroup(context.Background(), 321, GroupUser{
+			Email:     "alan@example.org",
+			FirstName: "Alan",
+			LastName:  "Turing",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if user.ID != 1414 {
+			t.Error("invalid user")
+		}
+	})
+}
+
+func TestClient_RemoveUserFromGroup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/remove_user_from_group" {
+				t.Error("invalid URL request")
+			}
+
+			reqBody, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fail()
+			}
+
+			expectedReqBody := `{"group_id":321,"user_id":1414}`
+			if string(reqBody) != expectedReqBody {
+				t.FailNow()
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"success": true, "errors": {}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		err := c.RemoveUserFromGroup(context.Background(), 321, 1414)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("non-zero balance", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"success": false, "errors": {"base": ["Cannot remove a user with a non-zero balance"]}}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		err := c.RemoveUserFromGroup(context.Background(), 321, 1414)
+		if !errors.Is(err, ErrNonZeroBalance) {
+			t.Fatalf("expected ErrNonZeroBalance, got %v", err)
+		}
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatal("expected a validation error")
+		}
+	})
+}
@@ -0,0 +1,194 @@
+package splitwise
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// LoginConfig configures LoginWithBrowser
+type LoginConfig struct {
+	// OAuth2Config is the registered application. Its RedirectURL is replaced by the address of the loopback listener,
+	// which should be registered as the callback URL of the application.
+	OAuth2Config
+
+	// Port is the port of the loopback listener on 127.0.0.1. A random port is used if it is zero.
+	Port int
+
+	// CallbackPath is the path of the redirect URL, "/callback" by default
+	CallbackPath string
+
+	// PKCE enables Proof Key for Code Exchange (RFC 7636) with the S256 challenge method
+	PKCE bool
+
+	// OpenBrowser is called with the authorize URL, e.g. to open it in the user's browser. The URL is always printed to
+	// Output, so the user can open it manually if OpenBrowser is nil or fails.
+	OpenBrowser func(authURL string) error
+
+	// Output receives the instructions for the user. os.Stderr is used if it is nil.
+	Output io.Writer
+
+	// Store, if set, receives the token of the Account and the returned AuthProvider reads the token from it, so the
+	// login survives restarts of the application
+	Store   TokenStore
+	Account string
+}
+
+type loginResult struct {
+	code string
+	err  error
+}
+
+// LoginWithBrowser runs the OAuth 2.0 authorization code flow for the command-line tools. It starts a temporary HTTP
+// listener on 127.0.0.1 as the redirect URL, asks the user to open the authorize URL, validates the state of the
+// callback, exchanges the code for a token and returns an AuthProvider working with it. It waits until a callback with
+// the right state and either a code or an error is received, or ctx is done. The other requests to the callback are
+// rejected with 400 Bad Request.
+func LoginWithBrowser(ctx context.Context, cfg LoginConfig) (AuthProvider, error) {
+	callbackPath := cfg.CallbackPath
+	if callbackPath == "" {
+		callbackPath = "/callback"
+	}
+
+	output := cfg.Output
+	if output == nil {
+		output = os.Stderr
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(cfg.Port)))
+	if err != nil {
+		return nil, err
+	}
+
+	config := cfg.OAuth2Config
+	config.RedirectURL = "http://" + listener.Addr().String() + callbackPath
+
+	state, err := randomURLSafeString(32)
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	params := url.Values{"state": {state}}
+	exchangeParams := url.Values{}
+	if cfg.PKCE {
+		verifier, err := randomURLSafeString(32)
+		if err != nil {
+			_ = listener.Close()
+			return nil, err
+		}
+
+		challenge := sha256.Sum256([]byte(verifier))
+		params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+		params.Set("code_challenge_method", "S256")
+		exchangeParams.Set("code_verifier", verifier)
+	}
+
+	results := make(chan loginResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, func(rw http.ResponseWriter, req *http.Request) {
+		result, ok := loginCallbackResult(req, state)
+		if !ok {
+			// Requests which are not the redirect of this login, e.g. from other local programs, don't end the login
+			http.Error(rw, result.err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if result.err != nil {
+			http.Error(rw, "Login failed: "+result.err.Error(), http.StatusBadRequest)
+		} else {
+			_, _ = io.WriteString(rw, "Logged in to Splitwise. You can close this window now.")
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	authURL := config.authCodeURL(params)
+	_, _ = fmt.Fprintf(output, "Open the following URL in your browser to log in to Splitwise:\n\n%s\n\n", authURL)
+	if cfg.OpenBrowser != nil {
+		if err := cfg.OpenBrowser(authURL); err != nil {
+			_, _ = fmt.Fprintf(output, "Could not open the browser: %v\n", err)
+		}
+	}
+
+	var result loginResult
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, err := config.exchange(ctx, result.code, exchangeParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Store != nil {
+		err = cfg.Store.Save(ctx, cfg.Account, token)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewTokenStoreAuth(cfg.Store, cfg.Account, config.Refresh), nil
+	}
+
+	return NewOAuth2Auth(config, token), nil
+}
+
+// loginCallbackResult extracts the authorization code or the error from the callback request after validating its
+// state. It returns false if the request is not a valid redirect of the login, which should not end the login.
+func loginCallbackResult(req *http.Request, state string) (loginResult, bool) {
+	query := req.URL.Query()
+
+	if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state)) != 1 {
+		return loginResult{err: ErrLoginStateMismatch}, false
+	}
+
+	if code := query.Get("error"); code != "" {
+		return loginResult{err: &OAuth2Error{Code: code, Description: query.Get("error_description")}}, true
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return loginResult{err: fmt.Errorf("oauth2: callback has no authorization code")}, false
+	}
+
+	return loginResult{code: code}, true
+}
+
+func randomURLSafeString(size int) (string, error) {
+	data := make([]byte, size)
+	_, err := io.ReadFull(rand.Reader, data)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,199 @@
+package splitwise
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// newFakeAuthServer starts a local authorization server which approves every authorization request right away and
+// redirects the user with the given state, or the received one if it is empty
+func newFakeAuthServer(t *testing.T, state string) *httptest.Server {
+	var challenge string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/authorize", func(rw http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		challenge = query.Get("code_challenge")
+
+		callbackState := state
+		if callbackState == "" {
+			callbackState = query.Get("state")
+		}
+
+		redirect := query.Get("redirect_uri") + "?" + url.Values{"code": {"the-code"}, "state": {callbackState}}.Encode()
+		http.Redirect(rw, req, redirect, http.StatusFound)
+	})
+	mux.HandleFunc("/oauth/token", func(rw http.ResponseWriter, req *http.Request) {
+		err := req.ParseForm()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if req.PostForm.Get("code") != "the-code" {
+			t.Error("invalid authorization code")
+		}
+
+		verifier := sha256.Sum256([]byte(req.PostForm.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(verifier[:]) != challenge {
+			t.Error("code verifier does not match the challenge")
+		}
+
+		_, _ = rw.Write([]byte(`{"access_token": "access", "token_type": "bearer"}`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestLoginWithBrowser(t *testing.T) {
+	openBrowser := func(authURL string) error {
+		go func() {
+			res, err := http.Get(authURL)
+			if err == nil {
+				_ = res.Body.Close()
+			}
+		}()
+
+		return nil
+	}
+
+	t.Run("success", func(t *testing.T) {
+		server := newFakeAuthServer(t, "")
+		defer server.Close()
+
+		var output bytes.Buffer
+		store := NewMemoryTokenStore()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		ap, err := LoginWithBrowser(ctx, LoginConfig{
+			OAuth2Config: OAuth2Config{
+				ClientID: "client-id",
+				AuthURL:  server.URL + "/oauth/authorize",
+				TokenURL: server.URL + "/oauth/token",
+			},
+			PKCE:        true,
+			OpenBrowser: openBrowser,
+			Output:      &output,
+			Store:       store,
+			Account:     "john",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		token, err := ap.Auth()
+		if err != nil || token != "access" {
+			t.Errorf("unexpected token %q: %v", token, err)
+		}
+
+		if _, err := store.Load(context.Background(), "john"); err != nil {
+			t.Error("token is not saved in the store")
+		}
+
+		if !bytes.Contains(output.Bytes(), []byte(server.URL+"/oauth/authorize?")) {
+			t.Errorf("authorize URL is not printed: %s", output.String())
+		}
+	})
+
+	t.Run("state mismatch", func(t *testing.T) {
+		server := newFakeAuthServer(t, "")
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// The callbacks without the right state, or without a code, are rejected without ending the login
+		openBrowserAfterStrayRequests := func(authURL string) error {
+			parsed, err := url.Parse(authURL)
+			if err != nil {
+				return err
+			}
+			callback := parsed.Query().Get("redirect_uri")
+
+			strayQueries := []url.Values{
+				{},
+				{"code": {"the-code"}, "state": {"forged-state"}},
+				{"state": {parsed.Query().Get("state")}},
+			}
+			for _, query := range strayQueries {
+				res, err := http.Get(callback + "?" + query.Encode())
+				if err != nil {
+					return err
+				}
+				_ = res.Body.Close()
+
+				if res.StatusCode != http.StatusBadRequest {
+					t.Errorf("unexpected status %d for %v", res.StatusCode, query)
+				}
+			}
+
+			return openBrowser(authURL)
+		}
+
+		ap, err := LoginWithBrowser(ctx, LoginConfig{
+			OAuth2Config: OAuth2Config{
+				AuthURL:  server.URL + "/oauth/authorize",
+				TokenURL: server.URL + "/oauth/token",
+			},
+			PKCE:        true,
+			OpenBrowser: openBrowserAfterStrayRequests,
+			Output:      &bytes.Buffer{},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		token, err := ap.Auth()
+		if err != nil || token != "access" {
+			t.Errorf("unexpected token %q: %v", token, err)
+		}
+	})
+
+	t.Run("authorization error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		deny := func(authURL string) error {
+			parsed, err := url.Parse(authURL)
+			if err != nil {
+				return err
+			}
+
+			query := url.Values{"error": {"access_denied"}, "state": {parsed.Query().Get("state")}}
+			go func() {
+				res, err := http.Get(parsed.Query().Get("redirect_uri") + "?" + query.Encode())
+				if err == nil {
+					_ = res.Body.Close()
+				}
+			}()
+
+			return nil
+		}
+
+		_, err := LoginWithBrowser(ctx, LoginConfig{OpenBrowser: deny, Output: &bytes.Buffer{}})
+
+		var oauth2Err *OAuth2Error
+		if !errors.As(err, &oauth2Err) || oauth2Err.Code != "access_denied" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := LoginWithBrowser(ctx, LoginConfig{Output: &bytes.Buffer{}})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,257 @@
+package splitwise
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The types of the errors counted by Metrics
+const (
+	ErrorTypeInvalidToken     = "invalid_token"
+	ErrorTypePermissionDenied = "permission_denied"
+	ErrorTypeNotFound         = "not_found"
+	ErrorTypeRateLimited      = "rate_limited"
+	ErrorTypeServer           = "server"
+	ErrorTypeValidation       = "validation"
+	ErrorTypeOther            = "other"
+)
+
+// DefaultLatencyBuckets are the upper bounds of the latency histogram buckets in seconds, used if no bucket is given to
+// NewMetrics
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects the number of the API calls, their errors by type and their latency, per operation. It implements
+// expvar.Var, so it can be published by expvar.Publish, and Handler renders it in the Prometheus text format. A Metrics
+// is safe for concurrent use and can be shared by several clients.
+type Metrics struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	operations map[string]*OperationMetrics
+}
+
+// OperationMetrics are the metrics of a single operation, e.g. "Groups"
+type OperationMetrics struct {
+	// Requests is the number of the calls, including the failed ones
+	Requests uint64 `json:"requests"`
+
+	// Errors is the number of the failed calls by the type of their error, e.g. ErrorTypeNotFound
+	Errors map[string]uint64 `json:"errors"`
+
+	// Latency is the histogram of the durations of the calls, including the retries
+	Latency LatencyHistogram `json:"latency"`
+}
+
+// LatencyHistogram counts the durations in buckets. Counts are cumulative: Counts[i] is the number of the durations
+// less than or equal to Buckets[i] seconds, and the last one, which has no bound, is the number of all the durations.
+type LatencyHistogram struct {
+	Buckets []float64 `json:"buckets"`
+	Counts  []uint64  `json:"counts"`
+	Count   uint64    `json:"count"`
+	Sum     float64   `json:"sum"`
+}
+
+// NewMetrics returns a new Metrics with the given latency histogram buckets in seconds, or DefaultLatencyBuckets if
+// none is given
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &Metrics{
+		buckets:    sorted,
+		operations: map[string]*OperationMetrics{},
+	}
+}
+
+// WithMetrics makes the client record every API call in the metrics
+func WithMetrics(metrics *Metrics) ClientOption {
+	return func(c *client) {
+		c.metrics = metrics
+	}
+}
+
+// observe records an API call
+func (m *Metrics) observe(op string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	operation, ok := m.operations[op]
+	if !ok {
+		operation = &OperationMetrics{
+			Errors: map[string]uint64{},
+			Latency: LatencyHistogram{
+				Buckets: m.buckets,
+				Counts:  make([]uint64, len(m.buckets)+1),
+			},
+		}
+		m.operations[op] = operation
+	}
+
+	operation.Requests++
+	if err != nil {
+		operation.Errors[errorType(err)]++
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			operation.Latency.Counts[i]++
+		}
+	}
+	operation.Latency.Counts[len(m.buckets)]++
+	operation.Latency.Count++
+	operation.Latency.Sum += seconds
+}
+
+// errorType classifies an error returned by an API call
+func errorType(err error) string {
+	var validationErr *ValidationError
+
+	switch {
+	case errors.Is(err, ErrInvalidToken):
+		return ErrorTypeInvalidToken
+	case errors.Is(err, ErrPermissionDenied):
+		return ErrorTypePermissionDenied
+	case errors.Is(err, ErrRecordNotFound):
+		return ErrorTypeNotFound
+	case errors.Is(err, ErrRateLimited):
+		return ErrorTypeRateLimited
+	case errors.Is(err, ErrSplitwiseServer):
+		return ErrorTypeServer
+	case errors.As(err, &validationErr):
+		return ErrorTypeValidation
+	default:
+		return ErrorTypeOther
+	}
+}
+
+// Snapshot returns a copy of the metrics by operation
+func (m *Metrics) Snapshot() map[string]OperationMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[string]OperationMetrics, len(m.operations))
+	for op, operation := range m.operations {
+		errorCounts := make(map[string]uint64, len(operation.Errors))
+		for errType, count := range operation.Errors {
+			errorCounts[errType] = count
+		}
+
+		latency := operation.Latency
+		latency.Buckets = append([]float64(nil), latency.Buckets...)
+		latency.Counts = append([]uint64(nil), latency.Counts...)
+
+		snapshot[op] = OperationMetrics{
+			Requests: operation.Requests,
+			Errors:   errorCounts,
+			Latency:  latency,
+		}
+	}
+
+	return snapshot
+}
+
+// String returns the metrics as JSON, which makes Metrics an expvar.Var
+func (m *Metrics) String() string {
+	data, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+
+	return string(data)
+}
+
+// Handler returns an http.Handler rendering the metrics in the Prometheus text exposition format
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = m.WritePrometheus(rw)
+	})
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	snapshot := m.Snapshot()
+
+	ops := make([]string, 0, len(snapshot))
+	for op := range snapshot {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	var builder strings.Builder
+
+	builder.WriteString("# HELP splitwise_requests_total Number of the Splitwise API calls.\n")
+	builder.WriteString("# TYPE splitwise_requests_total counter\n")
+	for _, op := range ops {
+		fmt.Fprintf(&builder, "splitwise_requests_total{operation=%s} %d\n", promLabel(op), snapshot[op].Requests)
+	}
+
+	builder.WriteString("# HELP splitwise_errors_total Number of the failed Splitwise API calls by error type.\n")
+	builder.WriteString("# TYPE splitwise_errors_total counter\n")
+	for _, op := range ops {
+		errorCounts := snapshot[op].Errors
+
+		errTypes := make([]string, 0, len(errorCounts))
+		for errType := range errorCounts {
+			errTypes = append(errTypes, errType)
+		}
+		sort.Strings(errTypes)
+
+		for _, errType := range errTypes {
+			fmt.Fprintf(&builder, "splitwise_errors_total{operation=%s,type=%s} %d\n",
+				promLabel(op), promLabel(errType), errorCounts[errType])
+		}
+	}
+
+	builder.WriteString("# HELP splitwise_request_duration_seconds Latency of the Splitwise API calls.\n")
+	builder.WriteString("# TYPE splitwise_request_duration_seconds histogram\n")
+	for _, op := range ops {
+		latency := snapshot[op].Latency
+		for i, count := range latency.Counts {
+			bound := "+Inf"
+			if i < len(latency.Buckets) {
+				bound = promFloat(latency.Buckets[i])
+			}
+
+			fmt.Fprintf(&builder, "splitwise_request_duration_seconds_bucket{operation=%s,le=%s} %d\n",
+				promLabel(op), promLabel(bound), count)
+		}
+		fmt.Fprintf(&builder, "splitwise_request_duration_seconds_sum{operation=%s} %s\n",
+			promLabel(op), promFloat(latency.Sum))
+		fmt.Fprintf(&builder, "splitwise_request_duration_seconds_count{operation=%s} %d\n",
+			promLabel(op), latency.Count)
+	}
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+// promLabel quotes a label value as described in the Prometheus text exposition format
+func promLabel(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	return `"` + replacer.Replace(value) + `"`
+}
+
+func promFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,164 @@
+package splitwise
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_Metrics(t *testing.T) {
+	// Start a local HTTP server
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v3.0/get_groups":
+			_, _ = rw.Write([]byte(`{"groups": []}`))
+		case "/api/v3.0/get_current_user":
+			rw.WriteHeader(http.StatusUnauthorized)
+		case "/api/v3.0/get_currencies":
+			rw.WriteHeader(http.StatusTooManyRequests)
+		case "/api/v3.0/get_categories":
+			rw.WriteHeader(http.StatusInternalServerError)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	metrics := NewMetrics()
+	c := &client{
+		AuthProvider: NewAPIKeyAuth("api-key"),
+		baseURL:      server.URL,
+		client:       http.DefaultClient,
+		metrics:      metrics,
+	}
+
+	ctx := context.Background()
+	_, _ = c.Groups(ctx)
+	_, _ = c.Groups(ctx)
+	_, _ = c.GroupByID(ctx, 1)
+	_, _ = c.CurrentUser(ctx)
+	_, _ = c.Currencies(ctx)
+	_, _ = c.Categories(ctx)
+	_ = c.Do(ctx, http.MethodGet, "get_main_data", nil, nil, nil)
+
+	snapshot := metrics.Snapshot()
+
+	expected := map[string]struct {
+		requests uint64
+		errType  string
+	}{
+		"Groups":      {requests: 2},
+		"GroupByID":   {requests: 1, errType: ErrorTypeNotFound},
+		"CurrentUser": {requests: 1, errType: ErrorTypeInvalidToken},
+		"Currencies":  {requests: 1, errType: ErrorTypeRateLimited},
+		"Categories":  {requests: 1, errType: ErrorTypeServer},
+		"Do":          {requests: 1, errType: ErrorTypeNotFound},
+	}
+	if len(snapshot) != len(expected) {
+		t.Fatalf("unexpected operations %v", snapshot)
+	}
+
+	for op, e := range expected {
+		operation := snapshot[op]
+		if operation.Requests != e.requests || operation.Latency.Count != e.requests {
+			t.Errorf("unexpected metrics of %s: %+v", op, operation)
+		}
+
+		if e.errType == "" && len(operation.Errors) != 0 {
+			t.Errorf("unexpected errors of %s: %v", op, operation.Errors)
+		}
+		if e.errType != "" && (len(operation.Errors) != 1 || operation.Errors[e.errType] != 1) {
+			t.Errorf("unexpected errors of %s: %v", op, operation.Errors)
+		}
+	}
+}
+
+func TestMetrics_observe(t *testing.T) {
+	metrics := NewMetrics(1, 0.1)
+	metrics.observe("Groups", 50*time.Millisecond, nil)
+	metrics.observe("Groups", 500*time.Millisecond, &ValidationError{})
+	metrics.observe("Groups", 2*time.Second, errors.New("connection refused"))
+
+	latency := metrics.Snapshot()["Groups"].Latency
+	if len(latency.Buckets) != 2 || latency.Buckets[0] != 0.1 || latency.Buckets[1] != 1 {
+		t.Errorf("unexpected buckets %v", latency.Buckets)
+	}
+
+	expectedCounts := []uint64{1, 2, 3}
+	for i, count := range expectedCounts {
+		if latency.Counts[i] != count {
+			t.Errorf("unexpected counts %v", latency.Counts)
+			break
+		}
+	}
+
+	if latency.Sum != 2.55 {
+		t.Errorf("unexpected sum %v", latency.Sum)
+	}
+
+	errorCounts := metrics.Snapshot()["Groups"].Errors
+	if errorCounts[ErrorTypeValidation] != 1 || errorCounts[ErrorTypeOther] != 1 {
+		t.Errorf("unexpected errors %v", errorCounts)
+	}
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	metrics := NewMetrics(0.1, 1)
+	metrics.observe("Groups", 50*time.Millisecond, nil)
+	metrics.observe("Groups", 500*time.Millisecond, ErrSplitwiseServer)
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", contentType)
+	}
+
+	expected := `# HELP splitwise_requests_total Number of the Splitwise API calls.
+# TYPE splitwise_requests_total counter
+splitwise_requests_total{operation="Groups"} 2
+# HELP splitwise_errors_total Number of the failed Splitwise API calls by error type.
+# TYPE splitwise_errors_total counter
+splitwise_errors_total{operation="Groups",type="server"} 1
+# HELP splitwise_request_duration_seconds Latency of the Splitwise API calls.
+# TYPE splitwise_request_duration_seconds histogram
+splitwise_request_duration_seconds_bucket{operation="Groups",le="0.1"} 1
+splitwise_request_duration_seconds_bucket{operation="Groups",le="1"} 2
+splitwise_request_duration_seconds_bucket{operation="Groups",le="+Inf"} 2
+splitwise_request_duration_seconds_sum{operation="Groups"} 0.55
+splitwise_request_duration_seconds_count{operation="Groups"} 2
+`
+	if recorder.Body.String() != expected {
+		t.Errorf("unexpected output:\n%s", recorder.Body.String())
+	}
+}
+
+func TestMetrics_expvar(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.observe("Groups", 50*time.Millisecond, ErrRecordNotFound)
+
+	// Publishing is not tested, as the expvar registry is global and rejects publishing the same name twice
+	var v expvar.Var = metrics
+
+	var published map[string]OperationMetrics
+	err := json.Unmarshal([]byte(v.String()), &published)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if published["Groups"].Requests != 1 || published["Groups"].Errors[ErrorTypeNotFound] != 1 {
+		t.Errorf("unexpected metrics %+v", published)
+	}
+}
+
+func TestPromLabel(t *testing.T) {
+	if label := promLabel("a\"b\\c\nd"); label != `"a\"b\\c\nd"` {
+		t.Errorf("unexpected label %s", label)
+	}
+}
@@ -0,0 +1,63 @@
+package splitwise
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Handler sends an API request and returns its response. The operation of the request is available by passing its
+// context to OperationName.
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a Handler to run code around the API calls, e.g. logging, adding headers or returning mocked
+// responses without calling next
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds middlewares around every API call of the client. The first middleware is the outermost one.
+// The innermost handler authorizes and sends the request, including the retries, so the middlewares see each call
+// once. The returned response is handled as if it was received from the service.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// errNoResponse will be returned when a middleware returns neither a response nor an error
+var errNoResponse = errors.New("middleware returned no response")
+
+type operationNameKey struct{}
+
+// OperationName returns the logical operation of an API call, which is the name of the Client method, e.g. "Groups"
+// or "CreateExpenseByShare", or "Do" for the calls made by Do. It is empty if ctx does not belong to an API call.
+func OperationName(ctx context.Context) string {
+	name, _ := ctx.Value(operationNameKey{}).(string)
+
+	return name
+}
+
+func withOperationName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationNameKey{}, name)
+}
+
+// handler returns the handler sending the requests through the middlewares of the client
+func (c client) handler() Handler {
+	handler := Handler(c.do)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+
+	return func(req *http.Request) (*http.Response, error) {
+		res, err := handler(req)
+		if res == nil && err == nil {
+			return nil, errNoResponse
+		}
+
+		// The responses made up by the middlewares may have no body, unlike the ones of http.Client
+		if res != nil && res.Body == nil {
+			res.Body = http.NoBody
+		}
+
+		return res, err
+	}
+}
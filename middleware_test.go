@@ -0,0 +1,123 @@
+package splitwise
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("X-Team") != "payments" {
+				t.Error("header is not injected")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"groups": [{"id": 1}]}`))
+		}))
+		defer server.Close()
+
+		var calls []string
+		record := func(name string) Middleware {
+			return func(next Handler) Handler {
+				return func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, name+" "+OperationName(req.Context())+" "+req.URL.Path)
+					return next(req)
+				}
+			}
+		}
+		injectHeader := func(next Handler) Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Team", "payments")
+				return next(req)
+			}
+		}
+
+		c := NewClient(NewAPIKeyAuth("api-key"), WithBaseURL(server.URL),
+			WithMiddleware(record("outer"), record("inner")),
+			WithMiddleware(injectHeader),
+		)
+
+		groups, err := c.Groups(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(groups) != 1 {
+			t.Errorf("unexpected groups %+v", groups)
+		}
+
+		expected := []string{"outer Groups /api/v3.0/get_groups", "inner Groups /api/v3.0/get_groups"}
+		if strings.Join(calls, ", ") != strings.Join(expected, ", ") {
+			t.Errorf("unexpected calls %q", calls)
+		}
+	})
+
+	t.Run("short circuit", func(t *testing.T) {
+		mock := func(next Handler) Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				if OperationName(req.Context()) != "DeleteGroup" {
+					return next(req)
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader(`{"success": false, "errors": {"base": ["Mocked"]}}`)),
+					Request:    req,
+				}, nil
+			}
+		}
+
+		c := NewClient(NewAPIKeyAuth("api-key"), WithBaseURL("http://127.0.0.1:0"), WithMiddleware(mock))
+
+		err := c.DeleteGroup(context.Background(), 1)
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Errors.String() != "Mocked" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("nil body", func(t *testing.T) {
+		c := NewClient(NewAPIKeyAuth("api-key"), WithMiddleware(func(next Handler) Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusNoContent}, nil
+			}
+		}))
+
+		currencies, err := c.Currencies(context.Background())
+		if err != nil || len(currencies) != 0 {
+			t.Fatalf("unexpected currencies %+v: %v", currencies, err)
+		}
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		c := NewClient(NewAPIKeyAuth("api-key"), WithMiddleware(func(next Handler) Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				return nil, nil
+			}
+		}))
+
+		_, err := c.Currencies(context.Background())
+		if !errors.Is(err, errNoResponse) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
+
+func TestOperationName(t *testing.T) {
+	if name := OperationName(context.Background()); name != "" {
+		t.Errorf("unexpected operation name %q", name)
+	}
+
+	if name := OperationName(withOperationName(context.Background(), "Groups")); name != "Groups" {
+		t.Errorf("unexpected operation name %q", name)
+	}
+}
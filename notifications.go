@@ -0,0 +1,123 @@
+package splitwise
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Notifications contains methods to access the activity feed of the current user
+type Notifications interface {
+	// Notifications returns a list of recent activity on the current user's account. The most recent notifications are
+	// returned first.
+	Notifications(ctx context.Context, opts ...NotificationsOption) ([]Notification, error)
+}
+
+// NotificationType is the kind of activity that a notification is about
+type NotificationType int
+
+const (
+	NotificationExpenseAdded             NotificationType = 0
+	NotificationExpenseUpdated           NotificationType = 1
+	NotificationExpenseDeleted           NotificationType = 2
+	NotificationCommentAdded             NotificationType = 3
+	NotificationAddedToGroup             NotificationType = 4
+	NotificationRemovedFromGroup         NotificationType = 5
+	NotificationGroupDeleted             NotificationType = 6
+	NotificationGroupSettingsChanged     NotificationType = 7
+	NotificationAddedAsFriend            NotificationType = 8
+	NotificationRemovedAsFriend          NotificationType = 9
+	NotificationNews                     NotificationType = 10
+	NotificationDebtSimplification       NotificationType = 11
+	NotificationGroupUndeleted           NotificationType = 12
+	NotificationExpenseUndeleted         NotificationType = 13
+	NotificationGroupCurrencyConversion  NotificationType = 14
+	NotificationFriendCurrencyConversion NotificationType = 15
+)
+
+var notificationTypeNames = map[NotificationType]string{
+	NotificationExpenseAdded:             "expense added",
+	NotificationExpenseUpdated:           "expense updated",
+	NotificationExpenseDeleted:           "expense deleted",
+	NotificationCommentAdded:             "comment added",
+	NotificationAddedToGroup:             "added to group",
+	NotificationRemovedFromGroup:         "removed from group",
+	NotificationGroupDeleted:             "group deleted",
+	NotificationGroupSettingsChanged:     "group settings changed",
+	NotificationAddedAsFriend:            "added as friend",
+	NotificationRemovedAsFriend:          "removed as friend",
+	NotificationNews:                     "news",
+	NotificationDebtSimplification:       "debt simplification",
+	NotificationGroupUndeleted:           "group undeleted",
+	NotificationExpenseUndeleted:         "expense undeleted",
+	NotificationGroupCurrencyConversion:  "group currency conversion",
+	NotificationFriendCurrencyConversion: "friend currency conversion",
+}
+
+func (t NotificationType) String() string {
+	name, ok := notificationTypeNames[t]
+	if !ok {
+		return "unknown notification type " + strconv.Itoa(int(t))
+	}
+
+	return name
+}
+
+type Notification struct {
+	ID         uint64             `json:"id"`
+	Type       NotificationType   `json:"type"`
+	CreatedAt  time.Time          `json:"created_at"`
+	CreatedBy  uint64             `json:"created_by"`
+	Source     NotificationSource `json:"source"`
+	ImageURL   string             `json:"image_url"`
+	ImageShape string             `json:"image_shape"`
+	// Content is a HTML-formatted message describing the notification
+	Content string `json:"content"`
+}
+
+// NotificationSource is the resource that a notification refers to
+type NotificationSource struct {
+	Type string `json:"type"`
+	ID   uint64 `json:"id"`
+	URL  string `json:"url"`
+}
+
+// NotificationsOption narrows down the notifications returned by Notifications
+type NotificationsOption func(query url.Values)
+
+// NotificationsUpdatedAfter returns only the notifications updated after the given time
+func NotificationsUpdatedAfter(t time.Time) NotificationsOption {
+	return func(query url.Values) {
+		query.Set("updated_after", t.UTC().Format(time.RFC3339))
+	}
+}
+
+// NotificationsLimit limits the number of the returned notifications. Zero means the maximum that the service allows.
+func NotificationsLimit(limit uint) NotificationsOption {
+	return func(query url.Values) {
+		query.Set("limit", strconv.FormatUint(uint64(limit), 10))
+	}
+}
+
+type notificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+}
+
+// Notifications returns a list of recent activity on the current user's account. The most recent notifications are
+// returned first.
+func (c client) Notifications(ctx context.Context, opts ...NotificationsOption) ([]Notification, error) {
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+
+	var response notificationsResponse
+	err := c.call(ctx, "Notifications", http.MethodGet, "get_notifications", query, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Notifications, nil
+}
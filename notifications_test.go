@@ -0,0 +1,76 @@
+package splitwise
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_Notifications(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.String() != "/api/v3.0/get_notifications?limit=10&updated_after=2021-10-23T07%3A15%3A37Z" {
+				t.Error("invalid URL request")
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{
+				"notifications": [
+					{
+						"id": 32514315,
+						"type": 0,
+						"created_at": "2019-08-24T14:15:22Z",
+						"created_by": 2,
+						"source": {
+							"type": "Expense",
+							"id": 865077,
+							"url": null
+						},
+						"image_url": "https://s3.amazonaws.com/splitwise/uploads/notifications/v2/0-venmo.png",
+						"image_shape": "square",
+						"content": "<strong>You</strong> paid <strong>Jon H.</strong>.<br><font color=\"#5bc5a7\">You paid $23.45</font>"
+					}
+				]
+			}`))
+		}))
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+		}
+
+		notifications, err := c.Notifications(context.Background(),
+			NotificationsUpdatedAfter(time.Date(2021, 10, 23, 7, 15, 37, 0, time.UTC)),
+			NotificationsLimit(10))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(notifications) != 1 {
+			t.Fatalf("expected 1 notification, got %d", len(notifications))
+		}
+
+		if notifications[0].Type != NotificationExpenseAdded {
+			t.Errorf("unexpected notification type: %s", notifications[0].Type)
+		}
+
+		if notifications[0].Source.ID != 865077 {
+			t.Error("invalid notification source")
+		}
+	})
+}
+
+func TestNotificationType_String(t *testing.T) {
+	if NotificationCommentAdded.String() != "comment added" {
+		t.Fail()
+	}
+
+	if NotificationType(99).String() != "unknown notification type 99" {
+		t.Fail()
+	}
+}
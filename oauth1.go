@@ -0,0 +1,211 @@
+package splitwise
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewOAuth1Auth returns a new AuthProvider that signs the requests according to OAuth 1.0a (RFC 5849) with the
+// HMAC-SHA1 signature method
+func NewOAuth1Auth(consumerKey, consumerSecret, token, tokenSecret string) AuthProvider {
+	return &oauth1AuthProvider{
+		consumerKey:    consumerKey,
+		consumerSecret: consumerSecret,
+		token:          token,
+		tokenSecret:    tokenSecret,
+		now:            time.Now,
+		nonce:          newOAuth1Nonce,
+	}
+}
+
+type oauth1AuthProvider struct {
+	consumerKey    string
+	consumerSecret string
+	token          string
+	tokenSecret    string
+
+	now   func() time.Time
+	nonce func() (string, error)
+}
+
+// Auth is not supported by OAuth 1.0a as each request should be signed separately, so ErrRequestSigningRequired is
+// returned
+func (a oauth1AuthProvider) Auth() (string, error) {
+	return "", ErrRequestSigningRequired
+}
+
+// Sign signs the request, including its query and form body, and sets the Authorization header
+func (a oauth1AuthProvider) Sign(req *http.Request) error {
+	nonce, err := a.nonce()
+	if err != nil {
+		return err
+	}
+
+	oauthParams := map[string]string{
+		"oauth_consumer_key":     a.consumerKey,
+		"oauth_nonce":            nonce,
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(a.now().Unix(), 10),
+	}
+	if a.token != "" {
+		oauthParams["oauth_token"] = a.token
+	}
+
+	signature, err := a.signature(req, oauthParams)
+	if err != nil {
+		return err
+	}
+	oauthParams["oauth_signature"] = signature
+
+	keys := make([]string, 0, len(oauthParams))
+	for key := range oauthParams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, oauth1Escape(key)+`="`+oauth1Escape(oauthParams[key])+`"`)
+	}
+
+	req.Header.Set("Authorization", "OAuth "+strings.Join(parts, ", "))
+
+	return nil
+}
+
+// signature calculates the HMAC-SHA1 signature of the request (RFC 5849 section 3.4.2)
+func (a oauth1AuthProvider) signature(req *http.Request, oauthParams map[string]string) (string, error) {
+	baseString, err := oauth1SignatureBaseString(req, oauthParams)
+	if err != nil {
+		return "", err
+	}
+
+	key := oauth1Escape(a.consumerSecret) + "&" + oauth1Escape(a.tokenSecret)
+	mac := hmac.New(sha1.New, []byte(key))
+	_, _ = mac.Write([]byte(baseString))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// oauth1SignatureBaseString builds the signature base string of the request (RFC 5849 section 3.4.1)
+func oauth1SignatureBaseString(req *http.Request, oauthParams map[string]string) (string, error) {
+	params, err := url.ParseQuery(req.URL.RawQuery)
+	if err != nil {
+		return "", err
+	}
+
+	formParams, err := oauth1FormParams(req)
+	if err != nil {
+		return "", err
+	}
+	for key, values := range formParams {
+		params[key] = append(params[key], values...)
+	}
+
+	for key, value := range oauthParams {
+		params[key] = append(params[key], value)
+	}
+
+	// Parameters are sorted by their encoded names and the ones with the same name by their encoded values
+	pairs := make([][2]string, 0, len(params))
+	for key, values := range params {
+		for _, value := range values {
+			pairs = append(pairs, [2]string{oauth1Escape(key), oauth1Escape(value)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	normalized := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		normalized = append(normalized, pair[0]+"="+pair[1])
+	}
+
+	return strings.ToUpper(req.Method) + "&" +
+		oauth1Escape(oauth1BaseURI(req.URL)) + "&" +
+		oauth1Escape(strings.Join(normalized, "&")), nil
+}
+
+// oauth1FormParams returns the parameters of a form encoded body and restores the body to be sent afterwards
+func oauth1FormParams(req *http.Request) (url.Values, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return url.Values{}, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType != "application/x-www-form-urlencoded" {
+		return url.Values{}, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return url.ParseQuery(string(body))
+}
+
+// oauth1BaseURI returns the base string URI of the request (RFC 5849 section 3.4.1.2)
+func oauth1BaseURI(u *url.URL) string {
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	return scheme + "://" + host + path
+}
+
+// oauth1Escape percent-encodes a string as described in RFC 5849 section 3.6
+func oauth1Escape(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			builder.WriteByte(c)
+		} else {
+			builder.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+
+	return builder.String()
+}
+
+func newOAuth1Nonce() (string, error) {
+	nonce := make([]byte, 16)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(nonce), nil
+}
@@ -0,0 +1,115 @@
+package splitwise
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The test vectors are taken from RFC 5849
+
+func TestOAuth1SignatureBaseString(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/request?b5=%3D%253D&a3=a&c%40=&a2=r%20b", strings.NewReader("c2&a3=2+q"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	baseString, err := oauth1SignatureBaseString(req, map[string]string{
+		"oauth_consumer_key":     "9djdj82h48djs9d2",
+		"oauth_token":            "kkk9d7dh3k39sjv7",
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        "137131201",
+		"oauth_nonce":            "7d8f3e4a",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "POST&http%3A%2F%2Fexample.com%2Frequest&a2%3Dr%2520b%26a3%3D2%2520q" +
+		"%26a3%3Da%26b5%3D%253D%25253D%26c%2540%3D%26c2%3D%26oauth_consumer_" +
+		"key%3D9djdj82h48djs9d2%26oauth_nonce%3D7d8f3e4a%26oauth_signature_m" +
+		"ethod%3DHMAC-SHA1%26oauth_timestamp%3D137131201%26oauth_token%3Dkkk" +
+		"9d7dh3k39sjv7"
+	if baseString != expected {
+		t.Errorf("unexpected signature base string:\n%s\nexpected:\n%s", baseString, expected)
+	}
+
+	// The body should be still readable after signing
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != "c2&a3=2+q" {
+		t.Error("request body is not restored")
+	}
+}
+
+func TestOAuth1BaseURI(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "HTTP://EXAMPLE.COM:80/r%20v/X?id=123", nil)
+	if oauth1BaseURI(req.URL) != "http://example.com/r%20v/X" {
+		t.Errorf("unexpected base URI: %s", oauth1BaseURI(req.URL))
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://www.example.net:8080/?q=1", nil)
+	if oauth1BaseURI(req.URL) != "https://www.example.net:8080/" {
+		t.Errorf("unexpected base URI: %s", oauth1BaseURI(req.URL))
+	}
+}
+
+func TestOAuth1AuthProvider_Sign(t *testing.T) {
+	ap := &oauth1AuthProvider{
+		consumerKey:    "dpf43f3p2l4k3l03",
+		consumerSecret: "kd94hf93k423kf44",
+		token:          "nnch734d00sl2jdk",
+		tokenSecret:    "pfkkdhi9sl3r4s00",
+		now: func() time.Time {
+			return time.Unix(137131202, 0)
+		},
+		nonce: func() (string, error) {
+			return "chapoH", nil
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://photos.example.net/photos?file=vacation.jpg&size=original", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ap.Sign(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `OAuth oauth_consumer_key="dpf43f3p2l4k3l03", oauth_nonce="chapoH", ` +
+		`oauth_signature="MdpQcU8iPSUjWoN%2FUDMsK2sui9I%3D", oauth_signature_method="HMAC-SHA1", ` +
+		`oauth_timestamp="137131202", oauth_token="nnch734d00sl2jdk"`
+	if req.Header.Get("Authorization") != expected {
+		t.Errorf("unexpected authorization header:\n%s\nexpected:\n%s", req.Header.Get("Authorization"), expected)
+	}
+}
+
+func TestClient_OAuth1(t *testing.T) {
+	// Start a local HTTP server
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "OAuth ") {
+			t.Errorf("request is not signed: %q", req.Header.Get("Authorization"))
+		}
+
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(`{"currencies": []}`))
+	}))
+	defer server.Close()
+
+	c := &client{
+		AuthProvider: NewOAuth1Auth("consumer-key", "consumer-secret", "token", "token-secret"),
+		baseURL:      server.URL,
+		client:       http.DefaultClient,
+	}
+
+	_, err := c.Currencies(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
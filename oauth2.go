@@ -0,0 +1,269 @@
+package splitwise
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// OAuth2AuthURL is the Splitwise endpoint that users are redirected to, to authorize an application
+	OAuth2AuthURL = ServerAddress + "/oauth/authorize"
+
+	// OAuth2TokenURL is the Splitwise endpoint that exchanges authorization codes and refresh tokens for access tokens
+	OAuth2TokenURL = ServerAddress + "/oauth/token"
+
+	// tokenExpiryDelta is how earlier than its expiry a token is considered expired, to avoid using a token which
+	// expires on its way to the service
+	tokenExpiryDelta = 10 * time.Second
+
+	// tokenRefreshTimeout bounds refreshing a token by Auth, which has no context to be cancelled with
+	tokenRefreshTimeout = 30 * time.Second
+)
+
+// OAuth2Config describes an application registered on Splitwise for the OAuth 2.0 authorization code flow
+type OAuth2Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+
+	// AuthURL and TokenURL default to OAuth2AuthURL and OAuth2TokenURL if they are empty
+	AuthURL  string
+	TokenURL string
+
+	// HTTPClient is used to call the token endpoint. http.DefaultClient is used if it is nil.
+	HTTPClient *http.Client
+}
+
+// Token holds the credentials issued by the token endpoint. A zero Expiry means the token does not expire.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// expired reports whether the token is expired or is about to expire at the given time
+func (t *Token) expired(now time.Time) bool {
+	if t.Expiry.IsZero() {
+		return false
+	}
+
+	return !now.Add(tokenExpiryDelta).Before(t.Expiry)
+}
+
+// OAuth2Error will be returned when the token endpoint rejects a request
+type OAuth2Error struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *OAuth2Error) Error() string {
+	message := "oauth2: " + e.Code
+	if e.StatusCode != 0 {
+		message = fmt.Sprintf("oauth2: %d %s", e.StatusCode, e.Code)
+	}
+
+	if e.Description != "" {
+		message += ": " + e.Description
+	}
+
+	return message
+}
+
+// Is reports a rejected grant, e.g. a revoked refresh token, as ErrInvalidToken
+func (e *OAuth2Error) Is(target error) bool {
+	return target == ErrInvalidToken && (e.Code == "invalid_grant" || e.StatusCode == http.StatusUnauthorized)
+}
+
+// AuthCodeURL returns the URL of the consent page that the user should be redirected to. state is sent back to the
+// redirect URL and should be checked there to protect against CSRF.
+func (c OAuth2Config) AuthCodeURL(state string) string {
+	return c.authCodeURL(url.Values{"state": {state}})
+}
+
+func (c OAuth2Config) authCodeURL(params url.Values) string {
+	authURL := c.AuthURL
+	if authURL == "" {
+		authURL = OAuth2AuthURL
+	}
+
+	params.Set("response_type", "code")
+	params.Set("client_id", c.ClientID)
+	if c.RedirectURL != "" {
+		params.Set("redirect_uri", c.RedirectURL)
+	}
+
+	if strings.Contains(authURL, "?") {
+		return authURL + "&" + params.Encode()
+	}
+
+	return authURL + "?" + params.Encode()
+}
+
+// Exchange converts an authorization code received on the redirect URL into a token
+func (c OAuth2Config) Exchange(ctx context.Context, code string) (*Token, error) {
+	return c.exchange(ctx, code, url.Values{})
+}
+
+func (c OAuth2Config) exchange(ctx context.Context, code string, params url.Values) (*Token, error) {
+	params.Set("grant_type", "authorization_code")
+	params.Set("code", code)
+	if c.RedirectURL != "" {
+		params.Set("redirect_uri", c.RedirectURL)
+	}
+
+	return c.retrieveToken(ctx, params)
+}
+
+// Refresh uses the refresh token of the given token to get a new token. The refresh token is kept if the token endpoint
+// does not issue a new one.
+func (c OAuth2Config) Refresh(ctx context.Context, token *Token) (*Token, error) {
+	if token.RefreshToken == "" {
+		return nil, ErrTokenExpired
+	}
+
+	newToken, err := c.retrieveToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {token.RefreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if newToken.RefreshToken == "" {
+		newToken.RefreshToken = token.RefreshToken
+	}
+
+	return newToken, nil
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+func (c OAuth2Config) retrieveToken(ctx context.Context, params url.Values) (*Token, error) {
+	tokenURL := c.TokenURL
+	if tokenURL == "" {
+		tokenURL = OAuth2TokenURL
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	params.Set("client_id", c.ClientID)
+	params.Set("client_secret", c.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Accept", "application/json")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode/100 != 2 {
+		oauth2Err := &OAuth2Error{StatusCode: res.StatusCode}
+		if json.Unmarshal(body, oauth2Err) != nil || oauth2Err.Code == "" {
+			oauth2Err.Code = http.StatusText(res.StatusCode)
+		}
+
+		return nil, oauth2Err
+	}
+
+	var response tokenResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2: token endpoint returned no access token")
+	}
+
+	token := &Token{
+		AccessToken:  response.AccessToken,
+		TokenType:    response.TokenType,
+		RefreshToken: response.RefreshToken,
+	}
+	if response.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+// NewOAuth2Auth returns a new AuthProvider that is working with an OAuth 2.0 token. The token is refreshed
+// automatically when it is expired, with the context of the request being sent. It is safe for concurrent use.
+func NewOAuth2Auth(config OAuth2Config, token *Token) AuthProvider {
+	return &oauth2AuthProvider{
+		config: config,
+		token:  token,
+		now:    time.Now,
+		mu:     newAuthLock(),
+	}
+}
+
+type oauth2AuthProvider struct {
+	config OAuth2Config
+	now    func() time.Time
+
+	mu    authLock
+	token *Token
+}
+
+// Auth Provides the auth token header used in calling the APIs. Refreshing the token is limited to
+// tokenRefreshTimeout.
+func (a *oauth2AuthProvider) Auth() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
+
+	return a.AuthContext(ctx)
+}
+
+// AuthContext provides the auth token header used in calling the APIs, refreshing the token with ctx if needed
+func (a *oauth2AuthProvider) AuthContext(ctx context.Context) (string, error) {
+	err := a.mu.lock(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer a.mu.unlock()
+
+	if a.token == nil {
+		return "", ErrInvalidToken
+	}
+
+	if a.token.expired(a.now()) {
+		token, err := a.config.Refresh(ctx, a.token)
+		if err != nil {
+			return "", err
+		}
+
+		a.token = token
+	}
+
+	return a.token.AccessToken, nil
+}
@@ -0,0 +1,214 @@
+package splitwise
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOAuth2Config_AuthCodeURL(t *testing.T) {
+	config := OAuth2Config{
+		ClientID:    "client-id",
+		RedirectURL: "https://example.com/callback",
+	}
+
+	authURL, err := url.Parse(config.AuthCodeURL("random-state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if authURL.Scheme+"://"+authURL.Host+authURL.Path != OAuth2AuthURL {
+		t.Errorf("invalid authorize endpoint: %s", authURL)
+	}
+
+	query := authURL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != "client-id" ||
+		query.Get("redirect_uri") != "https://example.com/callback" || query.Get("state") != "random-state" {
+		t.Errorf("invalid authorize URL query: %s", authURL.RawQuery)
+	}
+}
+
+func TestOAuth2Config_Exchange(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Start a local token server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			err := req.ParseForm()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if req.PostForm.Get("grant_type") != "authorization_code" || req.PostForm.Get("code") != "the-code" ||
+				req.PostForm.Get("client_id") != "client-id" || req.PostForm.Get("client_secret") != "client-secret" {
+				t.Errorf("invalid token request: %v", req.PostForm)
+			}
+
+			rw.Header().Set("Content-Type", "application/json")
+			_, _ = rw.Write([]byte(`{"access_token": "access", "token_type": "bearer", "refresh_token": "refresh", "expires_in": 3600}`))
+		}))
+		defer server.Close()
+
+		config := OAuth2Config{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			TokenURL:     server.URL,
+		}
+
+		token, err := config.Exchange(context.Background(), "the-code")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if token.AccessToken != "access" || token.RefreshToken != "refresh" || token.Expiry.IsZero() {
+			t.Errorf("invalid token: %+v", token)
+		}
+	})
+
+	t.Run("invalid grant", func(t *testing.T) {
+		// Start a local token server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"error": "invalid_grant", "error_description": "code is expired"}`))
+		}))
+		defer server.Close()
+
+		config := OAuth2Config{TokenURL: server.URL}
+
+		_, err := config.Exchange(context.Background(), "the-code")
+
+		var oauth2Err *OAuth2Error
+		if !errors.As(err, &oauth2Err) || oauth2Err.Description != "code is expired" {
+			t.Fatalf("expected OAuth2Error, got %v", err)
+		}
+
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Error("expected invalid grant to be reported as ErrInvalidToken")
+		}
+	})
+}
+
+func TestOAuth2AuthProvider_Auth(t *testing.T) {
+	t.Run("valid token", func(t *testing.T) {
+		ap := NewOAuth2Auth(OAuth2Config{}, &Token{AccessToken: "access", Expiry: time.Now().Add(time.Hour)})
+
+		token, err := ap.Auth()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if token != "access" {
+			t.Fail()
+		}
+	})
+
+	t.Run("refresh expired token concurrently", func(t *testing.T) {
+		var refreshes int32
+
+		// Start a local token server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&refreshes, 1)
+
+			err := req.ParseForm()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if req.PostForm.Get("grant_type") != "refresh_token" || req.PostForm.Get("refresh_token") != "refresh" {
+				t.Errorf("invalid refresh request: %v", req.PostForm)
+			}
+
+			_, _ = rw.Write([]byte(`{"access_token": "new-access", "token_type": "bearer", "expires_in": 3600}`))
+		}))
+		defer server.Close()
+
+		ap := NewOAuth2Auth(
+			OAuth2Config{TokenURL: server.URL},
+			&Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)},
+		)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				token, err := ap.Auth()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if token != "new-access" {
+					t.Errorf("unexpected token %q", token)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if refreshes != 1 {
+			t.Errorf("expected a single refresh, got %d", refreshes)
+		}
+	})
+
+	t.Run("expired token without refresh token", func(t *testing.T) {
+		ap := NewOAuth2Auth(OAuth2Config{}, &Token{AccessToken: "access", Expiry: time.Now().Add(-time.Minute)})
+
+		_, err := ap.Auth()
+		if !errors.Is(err, ErrTokenExpired) {
+			t.Fatalf("expected ErrTokenExpired, got %v", err)
+		}
+	})
+
+	t.Run("refresh with the request context", func(t *testing.T) {
+		// Start a local token server which never responds in time
+		hang := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			<-hang
+		}))
+		defer server.Close()
+		defer close(hang)
+
+		c := &client{
+			AuthProvider: NewOAuth2Auth(
+				OAuth2Config{TokenURL: server.URL},
+				&Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)},
+			),
+			baseURL: server.URL,
+			client:  http.DefaultClient,
+		}
+
+		// The requests waiting for the refresh should give up with their own contexts as well
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+				defer cancel()
+
+				_, err := c.Groups(ctx)
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("expected context.DeadlineExceeded, got %v", err)
+				}
+			}()
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("requests are blocked by the refresh")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package splitwise
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of the requests with a token bucket, which is refilled by a fixed number of tokens per
+// second up to its burst size. Each request takes a token and waits for one if the bucket is empty. A RateLimiter is
+// safe for concurrent use and can be shared by several clients using the same credentials, to keep them under the
+// same limit.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing requestsPerSecond requests per second on average and bursts of up to
+// burst requests. The bucket starts full. A burst less than 1 is treated as 1. NewRateLimiter panics if
+// requestsPerSecond is not positive; pass no limiter to WithRateLimiter to send the requests without a limit.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if !(requestsPerSecond > 0) {
+		panic(fmt.Sprintf("splitwise: non-positive rate %v for NewRateLimiter", requestsPerSecond))
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		now:    time.Now,
+		tokens: float64(burst),
+	}
+}
+
+// WithRateLimiter makes the client wait on the limiter before sending each request, including the retries
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// Wait blocks until a request is allowed or ctx is done. It returns an error without waiting if the deadline of ctx
+// would pass before the request is allowed.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	if !fitsDeadline(ctx, delay) {
+		l.cancel()
+		return fmt.Errorf("%w: rate limiter would wait for %s", context.DeadlineExceeded, delay)
+	}
+
+	err := sleep(ctx, delay)
+	if err != nil {
+		l.cancel()
+		return err
+	}
+
+	return nil
+}
+
+// reserve takes a token from the bucket and returns how long to wait until it is actually available
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token which was not used
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
@@ -0,0 +1,134 @@
+package splitwise
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_reserve(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(2, 3)
+	limiter.now = func() time.Time {
+		return now
+	}
+
+	// The burst is allowed at once
+	for i := 0; i < 3; i++ {
+		if delay := limiter.reserve(); delay != 0 {
+			t.Fatalf("request %d is delayed by %s", i, delay)
+		}
+	}
+
+	if delay := limiter.reserve(); delay != 500*time.Millisecond {
+		t.Fatalf("unexpected delay %s", delay)
+	}
+	if delay := limiter.reserve(); delay != time.Second {
+		t.Fatalf("unexpected delay %s", delay)
+	}
+
+	// The bucket is refilled with time but not beyond the burst
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		if delay := limiter.reserve(); delay != 0 {
+			t.Fatalf("request %d is delayed by %s", i, delay)
+		}
+	}
+	if delay := limiter.reserve(); delay != 500*time.Millisecond {
+		t.Fatalf("unexpected delay %s", delay)
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	for _, rate := range []float64{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for rate %v", rate)
+				}
+			}()
+
+			NewRateLimiter(rate, 1)
+		}()
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		limiter := NewRateLimiter(100, 1)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+			t.Fatalf("requests are not limited, took %s", elapsed)
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		limiter := NewRateLimiter(1, 1)
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := limiter.Wait(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		limiter := NewRateLimiter(1, 1)
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+
+		err := limiter.Wait(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
+
+func TestClient_RateLimiter(t *testing.T) {
+	// Start a local HTTP server
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(`{"currencies": []}`))
+	}))
+	// Close the server when test finishes
+	defer server.Close()
+
+	// The clients share the limiter, so the second one waits for the first
+	limiter := NewRateLimiter(1, 1)
+	first := NewClient(NewAPIKeyAuth("api-key"), WithBaseURL(server.URL), WithRateLimiter(limiter))
+	second := NewClient(NewAPIKeyAuth("api-key"), WithBaseURL(server.URL), WithRateLimiter(limiter))
+
+	_, err := first.Currencies(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = second.Currencies(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package splitwise
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures retrying the failed requests with exponential backoff. Only the reads (GET requests) are
+// retried unless RetryMutations is set, as repeating a mutation may apply it twice.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one. Values less than 2 disable the retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, which is doubled for each next retry
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between the attempts. The request is not retried if the Retry-After header of the
+	// response asks for a longer delay. Zero means no limit.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction of the delay, between 0 and 1, which is randomly subtracted from it so that the clients
+	// don't retry all at the same time
+	Jitter float64
+
+	// RetryableStatusCodes are the status codes of the responses which are retried
+	RetryableStatusCodes []int
+
+	// RetryableError reports whether a transport error is retried. All the errors are retried if it is nil, except the
+	// ones caused by the context of the request.
+	RetryableError func(err error) bool
+
+	// RetryMutations enables retrying the requests other than GET
+	RetryMutations bool
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used by NewClient, which retries the reads up to 3 times on transport
+// errors, 429 and 5xx responses
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the client, DefaultRetryPolicy by default. Passing a policy with zero
+// MaxAttempts disables the retries.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *client) {
+		c.retryPolicy = &policy
+	}
+}
+
+// allows reports whether the request can be retried at all by the policy
+func (p *RetryPolicy) allows(req *http.Request) bool {
+	if p == nil || p.MaxAttempts < 2 {
+		return false
+	}
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead && !p.RetryMutations {
+		return false
+	}
+
+	// The body can not be sent again if there is no way to get a copy of it
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func (p *RetryPolicy) retryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *RetryPolicy) retryableError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if p.RetryableError == nil {
+		return true
+	}
+
+	return p.RetryableError(err)
+}
+
+// backoff returns the delay before the given retry, starting from 1
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+
+	return delay
+}
+
+// delay returns the delay before the given retry of a response, and false if it should not be retried
+func (p *RetryPolicy) delay(retry int, res *http.Response, now time.Time) (time.Duration, bool) {
+	if res != nil && (res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable) {
+		if delay, ok := parseRetryAfter(res.Header.Get("Retry-After"), now); ok {
+			if p.MaxDelay > 0 && delay > p.MaxDelay {
+				return 0, false
+			}
+
+			return delay, true
+		}
+	}
+
+	return p.backoff(retry), true
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	delay := date.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay, true
+}
+
+// fitsDeadline reports whether the context is still alive after the delay, as far as its deadline is concerned
+func fitsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+
+	return !ok || time.Until(deadline) > delay
+}
+
+// sleep waits for the delay unless the context is done earlier
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
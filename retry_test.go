@@ -0,0 +1,171 @@
+package splitwise
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+
+	return &policy
+}
+
+func TestClient_Retry(t *testing.T) {
+	t.Run("read", func(t *testing.T) {
+		attempts := 0
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			attempts++
+			switch attempts {
+			case 1:
+				rw.Header().Set("Retry-After", "0")
+				rw.WriteHeader(http.StatusServiceUnavailable)
+			case 2:
+				rw.WriteHeader(http.StatusInternalServerError)
+			default:
+				_, _ = rw.Write([]byte(`{"currencies": [{"currency_code": "USD", "unit": "$"}]}`))
+			}
+		}))
+		// Close the server when test finishes
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+			retryPolicy:  testRetryPolicy(),
+		}
+
+		currencies, err := c.Currencies(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(currencies) != 1 || attempts != 3 {
+			t.Fatalf("unexpected result %+v after %d attempts", currencies, attempts)
+		}
+	})
+
+	t.Run("give up", func(t *testing.T) {
+		attempts := 0
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			attempts++
+			rw.WriteHeader(http.StatusInternalServerError)
+		}))
+		// Close the server when test finishes
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+			retryPolicy:  testRetryPolicy(),
+		}
+
+		_, err := c.Currencies(context.Background())
+		if !errors.Is(err, ErrSplitwiseServer) || attempts != 3 {
+			t.Fatalf("unexpected error %v after %d attempts", err, attempts)
+		}
+	})
+
+	t.Run("mutation", func(t *testing.T) {
+		var bodies []string
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				rw.WriteHeader(http.StatusBadGateway)
+				return
+			}
+
+			_, _ = rw.Write([]byte(`{"comment": {"id": 1, "content": "Hi"}}`))
+		}))
+		// Close the server when test finishes
+		defer server.Close()
+
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+			retryPolicy:  testRetryPolicy(),
+		}
+
+		_, err := c.CreateComment(context.Background(), 1, "Hi")
+		if err == nil || len(bodies) != 1 {
+			t.Fatalf("mutation is retried without opting in: %v, %d attempts", err, len(bodies))
+		}
+
+		bodies = nil
+		c.retryPolicy.RetryMutations = true
+		comment, err := c.CreateComment(context.Background(), 1, "Hi")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if comment.ID != 1 || len(bodies) != 2 || bodies[0] != bodies[1] {
+			t.Fatalf("unexpected result %+v with bodies %q", comment, bodies)
+		}
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		attempts := 0
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			attempts++
+			rw.Header().Set("Retry-After", "5")
+			rw.WriteHeader(http.StatusTooManyRequests)
+		}))
+		// Close the server when test finishes
+		defer server.Close()
+
+		policy := testRetryPolicy()
+		policy.MaxDelay = time.Minute
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+			retryPolicy:  policy,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		start := time.Now()
+		_, err := c.Currencies(ctx)
+		if err == nil || attempts != 1 || time.Since(start) > 500*time.Millisecond {
+			t.Fatalf("unexpected error %v after %d attempts in %s", err, attempts, time.Since(start))
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		delay time.Duration
+		ok    bool
+	}{
+		{value: "", ok: false},
+		{value: "3", delay: 3 * time.Second, ok: true},
+		{value: "-1", ok: false},
+		{value: "Tue, 01 Jun 2021 12:00:10 GMT", delay: 10 * time.Second, ok: true},
+		{value: "Tue, 01 Jun 2021 11:00:00 GMT", delay: 0, ok: true},
+		{value: "soon", ok: false},
+	}
+
+	for _, test := range tests {
+		delay, ok := parseRetryAfter(test.value, now)
+		if delay != test.delay || ok != test.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %t", test.value, delay, ok)
+		}
+	}
+}
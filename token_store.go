@@ -0,0 +1,416 @@
+package splitwise
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TokenStore persists the tokens of the accounts, so that they survive restarts of the application. The
+// implementations should be safe for concurrent use.
+type TokenStore interface {
+	// Load returns the token of the account, or ErrTokenNotFound if there is no token for it
+	Load(ctx context.Context, account string) (*Token, error)
+
+	// Save stores the token of the account, replacing the previous one
+	Save(ctx context.Context, account string, token *Token) error
+
+	// Delete removes the token of the account. Deleting a missing token is not an error.
+	Delete(ctx context.Context, account string) error
+}
+
+// NewMemoryTokenStore returns a TokenStore that keeps the tokens in memory
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{tokens: map[string]Token{}}
+}
+
+type memoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]Token
+}
+
+func (s *memoryTokenStore) Load(_ context.Context, account string) (*Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	token, ok := s.tokens[account]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+
+	return &token, nil
+}
+
+func (s *memoryTokenStore) Save(_ context.Context, account string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[account] = *token
+
+	return nil
+}
+
+func (s *memoryTokenStore) Delete(_ context.Context, account string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, account)
+
+	return nil
+}
+
+const (
+	// fileTokenStoreMagic prefixes the encrypted token files to tell them apart from the plain ones
+	fileTokenStoreMagic = "SWTS1"
+
+	fileTokenStoreSaltSize   = 16
+	fileTokenStoreIterations = 100000
+)
+
+// NewFileTokenStore returns a TokenStore that keeps the tokens of all the accounts in a single JSON file. The file is
+// written atomically with 0600 permissions. If passphrase is not empty, the file is encrypted with AES-GCM using a key
+// derived from the passphrase by PBKDF2-HMAC-SHA256.
+func NewFileTokenStore(path string, passphrase string) TokenStore {
+	return &fileTokenStore{
+		path:       path,
+		passphrase: passphrase,
+	}
+}
+
+type fileTokenStore struct {
+	path       string
+	passphrase string
+
+	mu sync.Mutex
+
+	// salt and aead cache the key derived from the passphrase, as deriving it is deliberately slow. The salt of the
+	// file is reused by the next writes, so the key is derived only once unless the file is replaced by someone else.
+	salt []byte
+	aead cipher.AEAD
+}
+
+func (s *fileTokenStore) Load(_ context.Context, account string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := tokens[account]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+
+	return &token, nil
+}
+
+func (s *fileTokenStore) Save(_ context.Context, account string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	tokens[account] = *token
+
+	return s.write(tokens)
+}
+
+func (s *fileTokenStore) Delete(_ context.Context, account string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := tokens[account]; !ok {
+		return nil
+	}
+	delete(tokens, account)
+
+	return s.write(tokens)
+}
+
+func (s *fileTokenStore) read() (map[string]Token, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Token{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.passphrase != "" {
+		data, err = s.decrypt(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tokens := map[string]Token{}
+	err = json.Unmarshal(data, &tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// write replaces the file by writing to a temporary file in the same directory and renaming it
+func (s *fileTokenStore) write(tokens map[string]Token) error {
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	if s.passphrase != "" {
+		data, err = s.encrypt(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	dir := filepath.Dir(s.path)
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+
+	err = file.Chmod(0600)
+	if err == nil {
+		_, err = file.Write(data)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), s.path)
+}
+
+// encrypt encrypts the data with a key derived from the passphrase. The result is the magic, the salt, the nonce and
+// the sealed data.
+func (s *fileTokenStore) encrypt(data []byte) ([]byte, error) {
+	salt := s.salt
+	if salt == nil {
+		salt = make([]byte, fileTokenStoreSaltSize)
+		_, err := io.ReadFull(rand.Reader, salt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	aead, err := s.aeadFor(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	result := append([]byte(fileTokenStoreMagic), salt...)
+	result = append(result, nonce...)
+
+	return aead.Seal(result, nonce, data, []byte(fileTokenStoreMagic)), nil
+}
+
+func (s *fileTokenStore) decrypt(data []byte) ([]byte, error) {
+	headerSize := len(fileTokenStoreMagic) + fileTokenStoreSaltSize
+	if len(data) < headerSize || string(data[:len(fileTokenStoreMagic)]) != fileTokenStoreMagic {
+		return nil, ErrTokenStoreDecryption
+	}
+
+	salt := data[len(fileTokenStoreMagic):headerSize]
+	aead, err := s.aeadFor(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < headerSize+aead.NonceSize() {
+		return nil, ErrTokenStoreDecryption
+	}
+
+	nonce := data[headerSize : headerSize+aead.NonceSize()]
+	plain, err := aead.Open(nil, nonce, data[headerSize+aead.NonceSize():], []byte(fileTokenStoreMagic))
+	if err != nil {
+		return nil, ErrTokenStoreDecryption
+	}
+
+	return plain, nil
+}
+
+// aeadFor returns the cipher with the key derived from the passphrase and the salt, deriving the key only if the salt
+// is not the cached one
+func (s *fileTokenStore) aeadFor(salt []byte) (cipher.AEAD, error) {
+	if s.aead != nil && bytes.Equal(s.salt, salt) {
+		return s.aead, nil
+	}
+
+	key := pbkdf2SHA256([]byte(s.passphrase), salt, fileTokenStoreIterations, 32)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	s.salt = append([]byte(nil), salt...)
+	s.aead = aead
+
+	return aead, nil
+}
+
+// pbkdf2SHA256 derives a key from the password as described in RFC 8018 section 5.2, using HMAC-SHA256 as the
+// pseudorandom function
+func pbkdf2SHA256(password, salt []byte, iterations, keyLength int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLength := prf.Size()
+	blocks := (keyLength + hashLength - 1) / hashLength
+
+	key := make([]byte, 0, blocks*hashLength)
+	u := make([]byte, hashLength)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		var index [4]byte
+		binary.BigEndian.PutUint32(index[:], uint32(block))
+		prf.Write(index[:])
+		u = prf.Sum(u[:0])
+
+		t := make([]byte, hashLength)
+		copy(t, u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+
+		key = append(key, t...)
+	}
+
+	return key[:keyLength]
+}
+
+// TokenRefreshFunc returns a new token for an expired one, e.g. OAuth2Config.Refresh
+type TokenRefreshFunc func(ctx context.Context, token *Token) (*Token, error)
+
+// NewTokenStoreAuth returns a new AuthProvider that reads the token of the account from the store. The token is kept in
+// memory and read again only when it is expired, in case it is refreshed by another process sharing the store. If it is
+// still expired and refresh is not nil, the token is refreshed and the new token is saved to the store.
+func NewTokenStoreAuth(store TokenStore, account string, refresh TokenRefreshFunc) AuthProvider {
+	return &tokenStoreAuthProvider{
+		store:   store,
+		account: account,
+		refresh: refresh,
+		now:     time.Now,
+		mu:      newAuthLock(),
+	}
+}
+
+type tokenStoreAuthProvider struct {
+	store   TokenStore
+	account string
+	refresh TokenRefreshFunc
+	now     func() time.Time
+
+	// mu guards token and prevents refreshing it concurrently
+	mu    authLock
+	token *Token
+}
+
+// Auth Provides the auth token header used in calling the APIs. Loading and refreshing the token is limited to
+// tokenRefreshTimeout.
+func (a *tokenStoreAuthProvider) Auth() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
+
+	return a.AuthContext(ctx)
+}
+
+// AuthContext provides the auth token header used in calling the APIs, loading and refreshing the token with ctx if
+// needed
+func (a *tokenStoreAuthProvider) AuthContext(ctx context.Context) (string, error) {
+	err := a.mu.lock(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer a.mu.unlock()
+
+	if a.token != nil && !a.token.expired(a.now()) {
+		return a.token.AccessToken, nil
+	}
+
+	token, err := a.store.Load(ctx, a.account)
+	if err != nil {
+		return "", err
+	}
+
+	if token.expired(a.now()) {
+		if a.refresh == nil {
+			return "", ErrTokenExpired
+		}
+
+		token, err = a.refresh(ctx, token)
+		if err != nil {
+			return "", err
+		}
+
+		// The refreshed token is kept even if saving it fails, as the old refresh token may be revoked by now
+		a.token = token
+
+		err = a.store.Save(ctx, a.account, token)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	a.token = token
+
+	return token.AccessToken, nil
+}
+
+func (a *tokenStoreAuthProvider) String() string {
+	return fmt.Sprintf("token store (account %q)", a.account)
+}
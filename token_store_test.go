@@ -0,0 +1,223 @@
+package splitwise
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// Test vectors from RFC 7914 section 11
+	key := pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64)
+	expected := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if hex.EncodeToString(key) != expected {
+		t.Errorf("unexpected key: %x", key)
+	}
+
+	key = pbkdf2SHA256([]byte("password"), []byte("salt"), 4096, 32)
+	expected = "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"
+	if hex.EncodeToString(key) != expected {
+		t.Errorf("unexpected key: %x", key)
+	}
+}
+
+func testTokenStore(t *testing.T, store TokenStore) {
+	ctx := context.Background()
+
+	_, err := store.Load(ctx, "john")
+	if !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("expected ErrTokenNotFound, got %v", err)
+	}
+
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = store.Save(ctx, "john", &Token{AccessToken: "access", RefreshToken: "refresh", Expiry: expiry})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Save(ctx, "jane", &Token{AccessToken: "jane-access"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := store.Load(ctx, "john")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" || !token.Expiry.Equal(expiry) {
+		t.Errorf("invalid token: %+v", token)
+	}
+
+	err = store.Delete(ctx, "john")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.Load(ctx, "john")
+	if !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("expected ErrTokenNotFound, got %v", err)
+	}
+
+	token, err = store.Load(ctx, "jane")
+	if err != nil || token.AccessToken != "jane-access" {
+		t.Errorf("other accounts should be kept: %v %v", token, err)
+	}
+}
+
+func TestMemoryTokenStore(t *testing.T) {
+	testTokenStore(t, NewMemoryTokenStore())
+}
+
+func TestFileTokenStore(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tokens.json")
+		testTokenStore(t, NewFileTokenStore(path, ""))
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+			t.Errorf("unexpected file permissions: %v", info.Mode().Perm())
+		}
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tokens")
+		testTokenStore(t, NewFileTokenStore(path, "passphrase"))
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if bytes.Contains(data, []byte("jane-access")) {
+			t.Error("token is stored in plain text")
+		}
+
+		_, err = NewFileTokenStore(path, "wrong passphrase").Load(context.Background(), "jane")
+		if !errors.Is(err, ErrTokenStoreDecryption) {
+			t.Fatalf("expected ErrTokenStoreDecryption, got %v", err)
+		}
+	})
+
+	t.Run("cached key", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tokens")
+		store := NewFileTokenStore(path, "passphrase").(*fileTokenStore)
+
+		ctx := context.Background()
+		_ = store.Save(ctx, "john", &Token{AccessToken: "access"})
+		aead := store.aead
+
+		_ = store.Save(ctx, "jane", &Token{AccessToken: "jane-access"})
+		_, err := store.Load(ctx, "john")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if aead == nil || store.aead != aead {
+			t.Error("key is derived again")
+		}
+	})
+}
+
+// countingTokenStore counts the loads of the wrapped store
+type countingTokenStore struct {
+	TokenStore
+	loads int
+}
+
+func (s *countingTokenStore) Load(ctx context.Context, account string) (*Token, error) {
+	s.loads++
+	return s.TokenStore.Load(ctx, account)
+}
+
+func TestTokenStoreAuthProvider_Auth(t *testing.T) {
+	t.Run("valid token", func(t *testing.T) {
+		store := NewMemoryTokenStore()
+		_ = store.Save(context.Background(), "john", &Token{AccessToken: "access"})
+
+		token, err := NewTokenStoreAuth(store, "john", nil).Auth()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if token != "access" {
+			t.Fail()
+		}
+	})
+
+	t.Run("refresh expired token", func(t *testing.T) {
+		store := NewMemoryTokenStore()
+		_ = store.Save(context.Background(), "john", &Token{
+			AccessToken:  "access",
+			RefreshToken: "refresh",
+			Expiry:       time.Now().Add(-time.Minute),
+		})
+
+		refresh := func(ctx context.Context, token *Token) (*Token, error) {
+			if token.RefreshToken != "refresh" {
+				t.Error("invalid refresh token")
+			}
+
+			return &Token{AccessToken: "new-access", RefreshToken: "new-refresh", Expiry: time.Now().Add(time.Hour)}, nil
+		}
+
+		token, err := NewTokenStoreAuth(store, "john", refresh).Auth()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if token != "new-access" {
+			t.Fail()
+		}
+
+		saved, err := store.Load(context.Background(), "john")
+		if err != nil || saved.RefreshToken != "new-refresh" {
+			t.Error("refreshed token is not saved")
+		}
+	})
+
+	t.Run("cached token", func(t *testing.T) {
+		store := &countingTokenStore{TokenStore: NewMemoryTokenStore()}
+		_ = store.Save(context.Background(), "john", &Token{AccessToken: "access", Expiry: time.Now().Add(time.Hour)})
+
+		ap := NewTokenStoreAuth(store, "john", nil).(*tokenStoreAuthProvider)
+		for i := 0; i < 3; i++ {
+			token, err := ap.Auth()
+			if err != nil || token != "access" {
+				t.Fatalf("unexpected token %q: %v", token, err)
+			}
+		}
+
+		if store.loads != 1 {
+			t.Errorf("expected the token to be loaded once, loaded %d times", store.loads)
+		}
+
+		// The token is loaded again once it is expired, as it may have been refreshed by another process
+		_ = store.Save(context.Background(), "john", &Token{AccessToken: "other-access"})
+		ap.now = func() time.Time {
+			return time.Now().Add(2 * time.Hour)
+		}
+
+		token, err := ap.Auth()
+		if err != nil || token != "other-access" || store.loads != 2 {
+			t.Errorf("unexpected token %q after %d loads: %v", token, store.loads, err)
+		}
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		_, err := NewTokenStoreAuth(NewMemoryTokenStore(), "john", nil).Auth()
+		if !errors.Is(err, ErrTokenNotFound) {
+			t.Fatalf("expected ErrTokenNotFound, got %v", err)
+		}
+	})
+}
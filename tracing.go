@@ -0,0 +1,45 @@
+package splitwise
+
+import (
+	"context"
+)
+
+// The attributes set on the spans of the API calls
+const (
+	// AttrOperation is the logical operation of the call, e.g. "Groups"
+	AttrOperation = "splitwise.operation"
+
+	// AttrHTTPMethod is the HTTP method of the request
+	AttrHTTPMethod = "http.method"
+
+	// AttrHTTPStatusCode is the HTTP status code of the last response, if any was received
+	AttrHTTPStatusCode = "http.status_code"
+
+	// AttrRetries is the number of the retries made after the first attempt
+	AttrRetries = "splitwise.retries"
+)
+
+// Tracer starts a span for each API call of the client. It is meant to be a thin adapter over a tracing library such
+// as OpenTelemetry.
+type Tracer interface {
+	// StartSpan starts a span as a child of the span in ctx, if any, and returns a context carrying the new span. The
+	// returned context is used for sending the request, so the trace can be propagated by the transport.
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single API call being traced
+type Span interface {
+	// SetAttr sets an attribute of the span. The values are strings, ints or bools.
+	SetAttr(key string, value interface{})
+
+	// End finishes the span. err is the error returned by the call, or nil if it was successful.
+	End(err error)
+}
+
+// WithTracer makes the client trace every API call. The spans are named after the operations, e.g. "Groups", and
+// carry the AttrOperation, AttrHTTPMethod, AttrHTTPStatusCode and AttrRetries attributes.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *client) {
+		c.tracer = tracer
+	}
+}
@@ -0,0 +1,142 @@
+package splitwise
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	name   string
+	parent string
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+func (s *testSpan) SetAttr(key string, value interface{}) {
+	s.attrs[key] = value
+}
+
+func (s *testSpan) End(err error) {
+	s.err = err
+	s.ended = true
+}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parent, _ := ctx.Value(testSpanKey{}).(string)
+	span := &testSpan{name: name, parent: parent, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+
+	return context.WithValue(ctx, testSpanKey{}, name), span
+}
+
+func TestClient_Tracer(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		attempts := 0
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			attempts++
+			if attempts == 1 {
+				rw.WriteHeader(http.StatusBadGateway)
+				return
+			}
+
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"groups": []}`))
+		}))
+		defer server.Close()
+
+		// The span of the call should be available to the handlers sending the request
+		var handlerSpan string
+		middleware := func(next Handler) Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				handlerSpan, _ = req.Context().Value(testSpanKey{}).(string)
+				return next(req)
+			}
+		}
+
+		tracer := &testTracer{}
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+			retryPolicy:  testRetryPolicy(),
+			middlewares:  []Middleware{middleware},
+			tracer:       tracer,
+		}
+
+		ctx := context.WithValue(context.Background(), testSpanKey{}, "incoming")
+		_, err := c.Groups(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(tracer.spans) != 1 {
+			t.Fatalf("expected a span, got %d", len(tracer.spans))
+		}
+
+		span := tracer.spans[0]
+		if span.name != "Groups" || span.parent != "incoming" || !span.ended || span.err != nil {
+			t.Errorf("unexpected span %+v", span)
+		}
+		if handlerSpan != "Groups" {
+			t.Errorf("span is not propagated to the handlers, got %q", handlerSpan)
+		}
+
+		expected := map[string]interface{}{
+			AttrOperation:      "Groups",
+			AttrHTTPMethod:     http.MethodGet,
+			AttrHTTPStatusCode: http.StatusOK,
+			AttrRetries:        1,
+		}
+		for key, value := range expected {
+			if span.attrs[key] != value {
+				t.Errorf("expected %s to be %v, got %v", key, value, span.attrs[key])
+			}
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		// Start a local HTTP server
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		tracer := &testTracer{}
+		c := &client{
+			AuthProvider: NewAPIKeyAuth("api-key"),
+			baseURL:      server.URL,
+			client:       http.DefaultClient,
+			tracer:       tracer,
+		}
+
+		err := c.DeleteExpense(context.Background(), 1)
+		if !errors.Is(err, ErrRecordNotFound) {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		span := tracer.spans[0]
+		if span.name != "DeleteExpense" || span.err != err {
+			t.Errorf("unexpected span %+v", span)
+		}
+		if span.attrs[AttrHTTPMethod] != http.MethodPost || span.attrs[AttrHTTPStatusCode] != http.StatusNotFound ||
+			span.attrs[AttrRetries] != 0 {
+			t.Errorf("unexpected attributes %v", span.attrs)
+		}
+	})
+}
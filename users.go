@@ -1,9 +1,7 @@
 package splitwise
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"net/http"
 	"strconv"
 )
@@ -58,33 +56,8 @@ type CurrentUser struct {
 
 // CurrentUser returns information about the current user
 func (c client) CurrentUser(ctx context.Context) (*CurrentUser, error) {
-	url := c.baseURL + "/api/v3.0/get_current_user"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	token, err := c.AuthProvider.Auth()
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("Authorization", "Bearer "+token)
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
-
-	err = c.checkError(res)
-	if err != nil {
-		return nil, err
-	}
-
 	var response currentUserResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
+	err := c.call(ctx, "CurrentUser", http.MethodGet, "get_current_user", nil, nil, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -112,33 +85,8 @@ type User struct {
 
 // UserByID returns a user information by their id.
 func (c client) UserByID(ctx context.Context, id uint64) (*User, error) {
-	url := c.baseURL + "/api/v3.0/get_user/" + strconv.FormatUint(id, 10)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	token, err := c.AuthProvider.Auth()
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("Authorization", "Bearer "+token)
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
-
-	err = c.checkError(res)
-	if err != nil {
-		return nil, err
-	}
-
 	var response userResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
+	err := c.call(ctx, "UserByID", http.MethodGet, "get_user/"+strconv.FormatUint(id, 10), nil, nil, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -151,45 +99,13 @@ type updateUserResponse struct {
 }
 
 func (c client) UpdateUser(ctx context.Context, id uint64, fields ...UserUpdatableField) (*CurrentUser, error) {
-	url := c.baseURL + "/api/v3.0/update_user/" + strconv.FormatUint(id, 10)
-
 	body := map[string]interface{}{}
 	for _, field := range fields {
 		body[field.Key()] = field.Value()
 	}
 
-	rawBody, err := json.Marshal(&body)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(rawBody))
-	if err != nil {
-		return nil, err
-	}
-
-	token, err := c.AuthProvider.Auth()
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("Content-Type", "application/json")
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = res.Body.Close()
-	}()
-
-	err = c.checkError(res)
-	if err != nil {
-		return nil, err
-	}
-
 	var response updateUserResponse
-	err = json.NewDecoder(res.Body).Decode(&response)
+	err := c.call(ctx, "UpdateUser", http.MethodPost, "update_user/"+strconv.FormatUint(id, 10), nil, body, &response)
 	if err != nil {
 		return nil, err
 	}